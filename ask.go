@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ericbfriday/claude-go-containers/claude"
+	"github.com/ericbfriday/claude-go-containers/prompt"
+)
+
+func askCommand() command {
+	return command{
+		name:    "ask",
+		args:    "[--no-cache] [--no-stream] [--system TEXT] [--context FILE]... <prompt>...",
+		summary: "Send a prompt to Claude and print the response",
+		setFlags: func(fs *flag.FlagSet) handler {
+			noCache := fs.Bool("no-cache", false, "always invoke claude instead of reusing a cached response")
+			noStream := fs.Bool("no-stream", false, "print the response only once it is complete (always the case with --json)")
+			system := fs.String("system", "", "instruction placed before the prompt")
+			var files stringList
+			fs.Var(&files, "context", "embed this file in the prompt (repeatable)")
+			return func(a *app, args []string) (Output, error) {
+				text := strings.Join(args, " ")
+				if strings.TrimSpace(text) == "" {
+					return nil, usagef("a prompt is required")
+				}
+				built, err := new(prompt.Builder).WithSystem(*system).WithContext(files...).WithText(text).Build()
+				if err != nil {
+					return nil, err
+				}
+				client := claude.Client{Logger: a.logger, DryRun: a.dryRun, Metrics: a.metrics}
+				if !*noCache {
+					client.Cache = a.claudeCache()
+				}
+				ctx, stop := a.toolContext()
+				defer stop()
+				if !*noStream && !a.json {
+					w := &newlineWriter{w: a.out}
+					err := client.RunStream(ctx, built, w)
+					if ferr := w.finish(); err == nil {
+						err = ferr
+					}
+					return nil, err
+				}
+				response, err := client.Run(ctx, built)
+				if err != nil {
+					return nil, err
+				}
+				return answer{Prompt: text, Response: response}, nil
+			}
+		},
+	}
+}
+
+// stringList is a flag.Value collecting every occurrence of a repeatable
+// flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// claudeCache returns the on-disk cache for Claude responses, under the
+// user's cache directory. It returns nil, disabling caching, if there is no
+// cache directory.
+func (a *app) claudeCache() claude.Cache {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		a.logger.Debug("response cache disabled", "err", err)
+		return nil
+	}
+	return claude.DirCache{Dir: filepath.Join(dir, progName, "claude")}
+}
+
+// answer is Claude's response to a prompt.
+type answer struct {
+	Prompt   string `json:"prompt"`
+	Response string `json:"response"`
+}
+
+func (r answer) Text(w io.Writer) error {
+	if r.Response == "" || strings.HasSuffix(r.Response, "\n") {
+		_, err := io.WriteString(w, r.Response)
+		return err
+	}
+	_, err := fmt.Fprintln(w, r.Response)
+	return err
+}
+
+func (r answer) JSON(w io.Writer) error { return writeJSON(w, r) }
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestAsk(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls")
+	stub.Write(t, dir, "claude", `echo x >>"`+counter+`"; echo "you said: $(cat)"`)
+	stub.PrependPath(t, dir)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	calls := func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "\n")
+	}
+
+	steps := []struct {
+		name      string
+		args      []string
+		wantOut   string
+		wantCalls int
+	}{
+		{"first ask invokes claude", []string{"ask", "hello", "there"}, "you said: hello there\n", 1},
+		{"repeat is served from cache", []string{"ask", "hello", "there"}, "you said: hello there\n", 1},
+		{"no-cache bypasses cache", []string{"ask", "--no-cache", "hello", "there"}, "you said: hello there\n", 2},
+		{"json", []string{"--json", "ask", "hello", "there"}, `{"prompt":"hello there","response":"you said: hello there\n"}` + "\n", 2},
+		{"new prompt misses", []string{"ask", "bye"}, "you said: bye\n", 3},
+	}
+	for _, st := range steps {
+		var out, errOut bytes.Buffer
+		if code := run(st.args, &out, &errOut); code != exitOK {
+			t.Fatalf("%s: run(%q) = %d, want %d; stderr: %s", st.name, st.args, code, exitOK, errOut.String())
+		}
+		if out.String() != st.wantOut {
+			t.Errorf("%s: stdout = %q, want %q", st.name, out.String(), st.wantOut)
+		}
+		if n := calls(); n != st.wantCalls {
+			t.Errorf("%s: claude invoked %d times in total, want %d", st.name, n, st.wantCalls)
+		}
+	}
+}
+
+func TestAskErrors(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `echo "quota exceeded" >&2; exit 1`)
+	stub.PrependPath(t, dir)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantErrOut string
+	}{
+		{"no prompt", []string{"ask"}, exitUsage, "a prompt is required"},
+		{"blank prompt", []string{"ask", " "}, exitUsage, "a prompt is required"},
+		{"claude fails", []string{"ask", "hi"}, exitError, "quota exceeded"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != tt.wantCode {
+				t.Errorf("run(%q) = %d, want %d", tt.args, code, tt.wantCode)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErrOut)
+			}
+			if out.Len() != 0 {
+				t.Errorf("stdout = %q, want empty", out.String())
+			}
+		})
+	}
+}
+
+func TestAskBuildsPrompt(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `cat`)
+	stub.PrependPath(t, dir)
+	notes := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(notes, []byte("buy milk\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	args := []string{"ask", "--no-cache", "--system", "Be brief.", "--context", notes, "Summarize", "this"}
+	if code := run(args, &out, &errOut); code != exitOK {
+		t.Fatalf("run(%q) = %d, want %d; stderr: %s", args, code, exitOK, errOut.String())
+	}
+	want := "Be brief.\n\n" + notes + ":\n```\nbuy milk\n```\n\nSummarize this\n"
+	if out.String() != want {
+		t.Errorf("prompt sent to claude =\n%q\nwant\n%q", out.String(), want)
+	}
+
+	missing := filepath.Join(dir, "missing.txt")
+	errOut.Reset()
+	if code := run([]string{"ask", "--context", missing, "hi"}, &out, &errOut); code != exitError {
+		t.Errorf("ask with a missing context file = %d, want %d", code, exitError)
+	}
+	if !strings.Contains(errOut.String(), missing) {
+		t.Errorf("stderr = %q, want it to name %s", errOut.String(), missing)
+	}
+}
+
+func TestAskStream(t *testing.T) {
+	dir := t.TempDir()
+	// No trailing newline: the CLI adds one however the response is
+	// printed.
+	stub.Write(t, dir, "claude", `printf 'you said: %s' "$(cat)"`)
+	stub.PrependPath(t, dir)
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"streamed by default", []string{"ask", "--no-cache", "hi"}, "you said: hi\n"},
+		{"no-stream", []string{"ask", "--no-cache", "--no-stream", "hi"}, "you said: hi\n"},
+		{"json is buffered", []string{"--json", "ask", "--no-cache", "hi"}, `{"prompt":"hi","response":"you said: hi"}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != exitOK {
+				t.Fatalf("run(%q) = %d, want %d; stderr: %s", tt.args, code, exitOK, errOut.String())
+			}
+			if out.String() != tt.want {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ericbfriday/claude-go-containers/claude"
+	"github.com/ericbfriday/claude-go-containers/prompt"
+)
+
+// defaultBatchConcurrency is the default for batch --concurrency.
+const defaultBatchConcurrency = 4
+
+func batchCommand() command {
+	return command{
+		name:    "batch",
+		args:    "[--concurrency N] [--best-effort] [--no-cache] [--system TEXT] < prompts",
+		summary: "Send each line of stdin to Claude as a separate prompt, several at a time",
+		setFlags: func(fs *flag.FlagSet) handler {
+			concurrency := fs.Int("concurrency", defaultBatchConcurrency, "prompts in flight at once")
+			bestEffort := fs.Bool("best-effort", false, "run every prompt and report failures instead of stopping at the first")
+			noCache := fs.Bool("no-cache", false, "always invoke claude instead of reusing cached responses")
+			system := fs.String("system", "", "instruction placed before every prompt")
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 {
+					return nil, usagef("unexpected arguments: %v; prompts are read from stdin", args)
+				}
+				if *concurrency < 1 {
+					return nil, usagef("--concurrency must be at least 1")
+				}
+				var texts, prompts []string
+				err := scanLines(a.in, func(line string) error {
+					if line = strings.TrimSpace(line); line == "" {
+						return nil
+					}
+					p, err := new(prompt.Builder).WithSystem(*system).WithText(line).Build()
+					if err != nil {
+						return err
+					}
+					texts, prompts = append(texts, line), append(prompts, p)
+					return nil
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				client := &claude.Client{Logger: a.logger, DryRun: a.dryRun, Metrics: a.metrics}
+				if !*noCache {
+					client.Cache = a.claudeCache()
+				}
+				ctx, stop := a.toolContext()
+				defer stop()
+				report := batchReport{items: make([]batchItem, len(texts))}
+				for i, text := range texts {
+					report.items[i].Prompt = text
+				}
+				if !*bestEffort {
+					responses, err := client.RunBatch(ctx, prompts, *concurrency)
+					if err != nil {
+						return nil, err
+					}
+					for i, r := range responses {
+						report.items[i].Response = r
+					}
+					return report, nil
+				}
+				responses, errs := client.RunBatchBestEffort(ctx, prompts, *concurrency)
+				failed := 0
+				for i := range report.items {
+					report.items[i].Response = responses[i]
+					if errs[i] != nil {
+						report.items[i].Error = errs[i].Error()
+						failed++
+					}
+				}
+				if failed > 0 {
+					return report, fmt.Errorf("%d of %d prompts failed", failed, len(prompts))
+				}
+				return report, nil
+			}
+		},
+	}
+}
+
+// batchItem is the outcome of one prompt in a batch. Error is set instead
+// of Response if the prompt failed in --best-effort mode.
+type batchItem struct {
+	Prompt   string `json:"prompt"`
+	Response string `json:"response"`
+	Error    string `json:"error,omitempty"`
+}
+
+// batchReport is the result of the batch command, in the order the
+// prompts were read.
+type batchReport struct {
+	items []batchItem
+}
+
+// Text writes each prompt's 1-based position and text on a header line,
+// then its response or error, with a blank line between prompts.
+func (r batchReport) Text(w io.Writer) error {
+	for i, item := range r.items {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		body := item.Response
+		if item.Error != "" {
+			body = "error: " + item.Error
+		}
+		if _, err := fmt.Fprintf(w, "[%d] %s\n%s", i+1, item.Prompt, body); err != nil {
+			return err
+		}
+		if body != "" && !strings.HasSuffix(body, "\n") {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r batchReport) JSON(w io.Writer) error {
+	items := r.items
+	if items == nil {
+		items = []batchItem{}
+	}
+	return writeJSON(w, items)
+}
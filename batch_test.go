@@ -0,0 +1,54 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestBatch(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `p=$(cat); case "$p" in *fail*) echo "no thanks" >&2; exit 7 ;; esac; echo "re: $p"`)
+	stub.PrependPath(t, dir)
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{"in order", []string{"batch", "--no-cache"}, "one\n\n two \nthree\n", exitOK,
+			"[1] one\nre: one\n\n[2] two\nre: two\n\n[3] three\nre: three\n", ""},
+		{"json", []string{"--json", "batch", "--no-cache"}, "one\n", exitOK,
+			`[{"prompt":"one","response":"re: one\n"}]` + "\n", ""},
+		{"no prompts", []string{"batch", "--no-cache"}, "", exitOK, "", ""},
+		{"fail fast", []string{"batch", "--no-cache", "--concurrency", "1"}, "one\nfail\nthree\n", 7,
+			"", "batch: prompt 2: claude: exit status 7: no thanks\n"},
+		{"best effort", []string{"batch", "--no-cache", "--best-effort"}, "one\nfail\nthree\n", exitError,
+			"[1] one\nre: one\n\n[2] fail\nerror: claude: exit status 7: no thanks\n\n[3] three\nre: three\n", "batch: 1 of 3 prompts failed\n"},
+		{"best effort json", []string{"--json", "batch", "--no-cache", "--best-effort"}, "fail\n", exitError,
+			`[{"prompt":"fail","response":"","error":"claude: exit status 7: no thanks"}]` + "\n", "batch: 1 of 1 prompts failed\n"},
+		{"bad concurrency", []string{"batch", "--concurrency", "0"}, "", exitUsage, "", "--concurrency must be at least 1\n"},
+		{"arguments", []string{"batch", "hi"}, "", exitUsage, "", "prompts are read from stdin\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := newApp(strings.NewReader(tt.stdin), &out, &errOut).run(tt.args)
+			if code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, errOut.String())
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if !strings.HasSuffix(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr = %q, want it to end with %q", errOut.String(), tt.wantErrOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/claude"
+	"github.com/ericbfriday/claude-go-containers/internal/table"
+	"github.com/ericbfriday/claude-go-containers/opencode"
+	"github.com/ericbfriday/claude-go-containers/stats"
+)
+
+// benchPrompt is sent on every benchmark invocation so results are
+// comparable between runs and machines.
+const benchPrompt = "Reply with the single word: ok"
+
+func benchCommand() command {
+	return command{
+		name:    "bench",
+		args:    "[--iterations N] [--timeout D]",
+		summary: "Time claude and opencode on a fixed prompt and report latency",
+		setFlags: func(fs *flag.FlagSet) handler {
+			iterations := fs.Int("iterations", 5, "invocations per tool")
+			timeout := fs.Duration("timeout", time.Minute, "limit for each invocation")
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 {
+					return nil, usagef("unexpected arguments: %v", args)
+				}
+				if *iterations < 1 {
+					return nil, usagef("--iterations must be at least 1")
+				}
+				if *timeout <= 0 {
+					return nil, usagef("--timeout must be positive")
+				}
+				// The global --timeout applies to each invocation too.
+				limit := *timeout
+				if a.timeout > 0 {
+					limit = min(limit, a.timeout)
+				}
+				report := benchReport{rows: make([]benchRow, 0, 2)}
+				failed := 0
+				ctx, stop := interruptContext()
+				defer stop()
+				targets := a.benchTargets()
+				p := a.progress()
+				defer p.Finish()
+				done, total := 0, *iterations*len(targets)
+				p.Update(done, total)
+				for _, t := range targets {
+					row := runBench(ctx, t, *iterations, limit, func() {
+						done++
+						p.Update(done, total)
+					})
+					failed += row.Failed
+					report.rows = append(report.rows, row)
+				}
+				if failed > 0 {
+					return report, fmt.Errorf("%d of %d invocations failed", failed, *iterations*len(report.rows))
+				}
+				return report, nil
+			}
+		},
+	}
+}
+
+// benchTarget is a tool invocation to time.
+type benchTarget struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// benchTargets returns the tools the bench command times, each sending
+// benchPrompt. Responses are never served from the cache.
+func (a *app) benchTargets() []benchTarget {
+	c := claude.Client{Logger: a.logger, DryRun: a.dryRun, Metrics: a.metrics}
+	runner, lookErr := opencode.New(
+		opencode.WithLogger(a.logger),
+		opencode.WithDryRun(a.dryRun),
+		opencode.WithMetrics(a.metrics),
+	)
+	return []benchTarget{
+		{"claude", func(ctx context.Context) error {
+			_, err := c.Run(ctx, benchPrompt)
+			return err
+		}},
+		{"opencode", func(ctx context.Context) error {
+			if lookErr != nil {
+				return lookErr
+			}
+			res, err := runner.Exec(ctx, "run", benchPrompt)
+			if err != nil {
+				return err
+			}
+			return res.Err()
+		}},
+	}
+}
+
+// runBench invokes t iterations times, each bounded by timeout, and
+// summarizes the latency of the successful invocations. It calls ran, if
+// not nil, after every invocation.
+func runBench(ctx context.Context, t benchTarget, iterations int, timeout time.Duration, ran func()) benchRow {
+	row := benchRow{Tool: t.name, Runs: iterations}
+	var samples []time.Duration
+	var lastErr error
+	for range iterations {
+		runCtx, cancel := context.WithTimeout(ctx, timeout)
+		start := time.Now()
+		err := t.run(runCtx)
+		elapsed := time.Since(start)
+		cancel()
+		if ran != nil {
+			ran()
+		}
+		if err != nil {
+			row.Failed++
+			lastErr = err
+			continue
+		}
+		samples = append(samples, elapsed)
+	}
+	if lat, err := stats.Latencies(samples); err == nil {
+		row.Latency = &lat
+	}
+	if lastErr != nil {
+		row.Error = lastErr.Error()
+	}
+	return row
+}
+
+// benchRow is the benchmark result for one tool. Latency is nil when no
+// invocation succeeded; Error holds the last failure, if any.
+type benchRow struct {
+	Tool    string         `json:"tool"`
+	Runs    int            `json:"runs"`
+	Failed  int            `json:"failed"`
+	Latency *stats.Latency `json:"latency"`
+	Error   string         `json:"error,omitempty"`
+}
+
+// benchReport is the result of the bench command.
+type benchReport struct {
+	rows []benchRow
+}
+
+// Text renders the report as an aligned table, with a dash for latencies
+// of tools that never succeeded.
+func (r benchReport) Text(w io.Writer) error {
+	rows := [][]string{{"TOOL", "RUNS", "FAILED", "MIN", "MEDIAN", "P95", "ERROR"}}
+	for _, row := range r.rows {
+		lo, median, p95 := "-", "-", "-"
+		if lat := row.Latency; lat != nil {
+			lo, median, p95 = formatLatency(lat.Min), formatLatency(lat.Median), formatLatency(lat.P95)
+		}
+		rows = append(rows, []string{row.Tool, fmt.Sprint(row.Runs), fmt.Sprint(row.Failed), lo, median, p95, row.Error})
+	}
+	return table.Write(w, rows, nil)
+}
+
+func (r benchReport) JSON(w io.Writer) error {
+	rows := r.rows
+	if rows == nil {
+		rows = []benchRow{}
+	}
+	return writeJSON(w, rows)
+}
+
+// formatLatency rounds d to a tenth of a millisecond for display.
+func formatLatency(d time.Duration) string {
+	return d.Round(100 * time.Microsecond).String()
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestRunBench(t *testing.T) {
+	calls := 0
+	flaky := benchTarget{name: "flaky", run: func(ctx context.Context) error {
+		calls++
+		if calls%2 == 0 {
+			return errors.New("tool failed")
+		}
+		return nil
+	}}
+	row := runBench(context.Background(), flaky, 5, time.Second, nil)
+	if row.Tool != "flaky" || row.Runs != 5 || row.Failed != 2 || row.Error != "tool failed" {
+		t.Errorf("runBench() = %+v, want 5 runs with 2 failures", row)
+	}
+	if row.Latency == nil || row.Latency.Count != 3 {
+		t.Errorf("runBench() latency = %+v, want 3 samples", row.Latency)
+	}
+
+	slow := benchTarget{name: "slow", run: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	row = runBench(context.Background(), slow, 2, 10*time.Millisecond, nil)
+	if row.Failed != 2 || row.Latency != nil || !strings.Contains(row.Error, "deadline exceeded") {
+		t.Errorf("runBench() = %+v, want every run to time out", row)
+	}
+}
+
+func TestBenchCommand(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `cat >/dev/null; echo ok`)
+	stub.Write(t, dir, "opencode", `[ "$1" = run ] && echo ok`)
+	stub.PrependPath(t, dir)
+
+	var out, errOut bytes.Buffer
+	if code := run([]string{"bench", "--iterations", "3"}, &out, &errOut); code != exitOK {
+		t.Fatalf("bench = %d, want %d; stderr: %s", code, exitOK, errOut.String())
+	}
+	lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "TOOL  ") || !strings.Contains(lines[0], "MEDIAN  P95") {
+		t.Fatalf("bench output = %q, want a header and one row per tool", out.String())
+	}
+	for i, tool := range []string{"claude", "opencode"} {
+		if f := strings.Fields(lines[i+1]); len(f) != 6 || f[0] != tool || f[1] != "3" || f[2] != "0" {
+			t.Errorf("row %d = %q, want %s with 3 runs and no failures", i+1, lines[i+1], tool)
+		}
+	}
+
+	out.Reset()
+	if code := run([]string{"--json", "bench", "--iterations", "2"}, &out, &errOut); code != exitOK {
+		t.Fatalf("--json bench = %d, want %d; stderr: %s", code, exitOK, errOut.String())
+	}
+	var rows []benchRow
+	if err := json.Unmarshal(out.Bytes(), &rows); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if len(rows) != 2 || rows[0].Latency == nil || rows[0].Latency.Count != 2 {
+		t.Errorf("bench JSON = %+v, want two tools with two samples each", rows)
+	}
+}
+
+func TestBenchProgress(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `cat >/dev/null; echo ok`)
+	stub.Write(t, dir, "opencode", `echo ok`)
+	stub.PrependPath(t, dir)
+
+	for _, tty := range []bool{false, true} {
+		var out, errOut bytes.Buffer
+		a := newApp(strings.NewReader(""), &out, &errOut)
+		a.isTerminal = func(w io.Writer) bool { return tty && w == &errOut }
+		if code := a.run([]string{"bench", "--iterations", "2"}); code != exitOK {
+			t.Fatalf("bench = %d, want %d; stderr: %s", code, exitOK, errOut.String())
+		}
+		if !tty {
+			if errOut.Len() != 0 {
+				t.Errorf("stderr = %q, want no progress when it is not a terminal", errOut.String())
+			}
+			continue
+		}
+		const clear = "\r\x1b[K"
+		want := clear + "[0/4]" + clear + "[1/4]" + clear + "[2/4]" + clear + "[3/4]" + clear + "[4/4]" + clear
+		if errOut.String() != want {
+			t.Errorf("stderr = %q, want %q", errOut.String(), want)
+		}
+		if strings.Contains(out.String(), "[") {
+			t.Errorf("stdout = %q, want the report free of progress", out.String())
+		}
+	}
+
+	var out, errOut bytes.Buffer
+	a := newApp(strings.NewReader(""), &out, &errOut)
+	a.isTerminal = func(io.Writer) bool { return true }
+	if code := a.run([]string{"--quiet", "bench", "--iterations", "1"}); code != exitOK || errOut.Len() != 0 {
+		t.Errorf("--quiet bench = %d with stderr %q, want no progress", code, errOut.String())
+	}
+}
+
+func TestBenchCommandErrors(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `echo "not logged in" >&2; exit 1`)
+	stub.Write(t, dir, "opencode", `echo ok`)
+	stub.PrependPath(t, dir)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{"failing tool", []string{"bench", "--iterations", "2"}, exitError, "not logged in", "2 of 4 invocations failed"},
+		{"zero iterations", []string{"bench", "--iterations", "0"}, exitUsage, "", "--iterations must be at least 1"},
+		{"zero timeout", []string{"bench", "--timeout", "0s"}, exitUsage, "", "--timeout must be positive"},
+		{"extra args", []string{"bench", "x"}, exitUsage, "", "unexpected arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != tt.wantCode {
+				t.Errorf("run(%q) = %d, want %d", tt.args, code, tt.wantCode)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("stdout = %q, want it to contain %q", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErrOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ericbfriday/claude-go-containers/examples"
+)
+
+func calcCommand() command {
+	return command{
+		name:    "calc",
+		args:    "<n> [<op> <n>]... | min|max|avg <n>...",
+		summary: "Evaluate integer arithmetic with + - * / (quote * in shells), or aggregate a list",
+		words:   aggregateNames,
+		setFlags: func(fs *flag.FlagSet) handler {
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 && slices.Contains(aggregateNames, args[0]) {
+					return evalAggregate(args[0], args[1:])
+				}
+				result, err := evalTokens(args)
+				if err != nil {
+					return nil, err
+				}
+				return calcResult{Expression: strings.Join(args, " "), Result: result}, nil
+			}
+		},
+	}
+}
+
+// evalTokens evaluates an expression given as alternating operand and
+// operator tokens. Multiplication and division bind tighter than addition
+// and subtraction; operators of equal precedence associate to the left.
+//
+// Malformed expressions are reported as usage errors. Arithmetic errors
+// such as examples.ErrDivideByZero and examples.ErrOverflow, for a step
+// whose result does not fit in an int, are returned unchanged.
+func evalTokens(tokens []string) (int, error) {
+	if len(tokens)%2 == 0 {
+		return 0, usagef("expected an operand, then operator/operand pairs")
+	}
+
+	// First pass: fold * and / into terms. Second pass: add up the terms,
+	// subtracting those that follow a minus. They are not negated, since
+	// -math.MinInt does not fit even where the difference would.
+	var terms []int
+	var subtract []bool // whether each term follows a minus
+	term, err := parseOperand(tokens[0])
+	if err != nil {
+		return 0, err
+	}
+	for i := 1; i < len(tokens); i += 2 {
+		op := tokens[i]
+		n, err := parseOperand(tokens[i+1])
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "*":
+			if term, err = examples.MultiplyChecked(term, n); err != nil {
+				return 0, err
+			}
+		case "/":
+			if term, err = examples.Divide(term, n); err != nil {
+				return 0, err
+			}
+		case "+", "-":
+			terms = append(terms, term)
+			subtract = append(subtract, op == "-")
+			term = n
+		default:
+			return 0, usagef("unknown operator %q: want one of + - * /", op)
+		}
+	}
+	terms = append(terms, term)
+
+	total := terms[0]
+	for i, t := range terms[1:] {
+		if subtract[i] {
+			total, err = examples.SubtractChecked(total, t)
+		} else {
+			total, err = examples.AddChecked(total, t)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// aggregateNames are the calc operations that take a list of operands
+// rather than an infix expression.
+var aggregateNames = []string{"min", "max", "avg"}
+
+// evalAggregate applies the aggregate operation op to operands.
+func evalAggregate(op string, operands []string) (Output, error) {
+	nums := make([]int, len(operands))
+	for i, tok := range operands {
+		n, err := parseOperand(tok)
+		if err != nil {
+			return nil, err
+		}
+		nums[i] = n
+	}
+
+	var result json.Number
+	var err error
+	switch op {
+	case "min":
+		var n int
+		n, err = examples.Min(nums...)
+		result = json.Number(strconv.Itoa(n))
+	case "max":
+		var n int
+		n, err = examples.Max(nums...)
+		result = json.Number(strconv.Itoa(n))
+	case "avg":
+		var f float64
+		f, err = examples.Average(nums...)
+		result = json.Number(strconv.FormatFloat(f, 'f', -1, 64))
+	}
+	if errors.Is(err, examples.ErrEmpty) {
+		return nil, usagef("%s needs at least one operand", op)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return aggregateResult{Operation: op, Operands: nums, Result: result}, nil
+}
+
+func parseOperand(tok string) (int, error) {
+	n, err := strconv.Atoi(tok)
+	if err != nil {
+		return 0, usagef("invalid integer %q", tok)
+	}
+	return n, nil
+}
+
+// calcResult is the result of evaluating an expression.
+type calcResult struct {
+	Expression string `json:"expression"`
+	Result     int    `json:"result"`
+}
+
+func (r calcResult) Text(w io.Writer) error {
+	_, err := fmt.Fprintln(w, r.Result)
+	return err
+}
+
+func (r calcResult) JSON(w io.Writer) error { return writeJSON(w, r) }
+
+// aggregateResult is the result of an aggregate operation. Result is kept
+// as a number literal so min and max stay exact integers while avg can be
+// fractional.
+type aggregateResult struct {
+	Operation string      `json:"operation"`
+	Operands  []int       `json:"operands"`
+	Result    json.Number `json:"result"`
+}
+
+func (r aggregateResult) Text(w io.Writer) error {
+	_, err := fmt.Fprintln(w, r.Result)
+	return err
+}
+
+func (r aggregateResult) JSON(w io.Writer) error { return writeJSON(w, r) }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/examples"
+)
+
+func TestEvalTokens(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokens    string
+		expected  int
+		err       error
+		wantUsage bool
+	}{
+		{"single operand", "7", 7, nil, false},
+		{"addition", "2 + 3", 5, nil, false},
+		{"subtraction", "2 - 3", -1, nil, false},
+		{"multiplication", "4 * 3", 12, nil, false},
+		{"division", "7 / 2", 3, nil, false},
+		{"precedence", "2 + 3 * 4", 14, nil, false},
+		{"left associative subtraction", "10 - 3 - 2", 5, nil, false},
+		{"left associative division", "100 / 10 / 5", 2, nil, false},
+		{"mixed", "2 * 3 - 8 / 4 + 1", 5, nil, false},
+		{"negative operand", "-2 * -3", 6, nil, false},
+		{"divide by zero", "1 / 0", 0, examples.ErrDivideByZero, false},
+		{"divide by zero later term", "1 + 4 / 0", 0, examples.ErrDivideByZero, false},
+		{"addition overflow", "9223372036854775807 + 1", 0, examples.ErrOverflow, false},
+		{"multiplication overflow", "9223372036854775807 * 2", 0, examples.ErrOverflow, false},
+		{"multiplication overflow later term", "1 + 4611686018427387904 * 2", 0, examples.ErrOverflow, false},
+		{"division overflow", "-9223372036854775808 / -1", 0, examples.ErrOverflow, false},
+		{"subtraction overflow", "1 - -9223372036854775808", 0, examples.ErrOverflow, false},
+		{"subtraction of min", "-1 - -9223372036854775808", 9223372036854775807, nil, false},
+		{"subtraction to min", "-9223372036854775807 - 1", -9223372036854775808, nil, false},
+		{"subtraction overflow past min", "-9223372036854775807 - 2", 0, examples.ErrOverflow, false},
+		{"min times one", "-9223372036854775808 * 1", -9223372036854775808, nil, false},
+		{"empty", "", 0, nil, true},
+		{"dangling operator", "2 +", 0, nil, true},
+		{"unknown operator", "2 % 3", 0, nil, true},
+		{"invalid operand", "2 + x", 0, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := evalTokens(strings.Fields(tt.tokens))
+			var uerr *usageError
+			if got := errors.As(err, &uerr); got != tt.wantUsage {
+				t.Fatalf("evalTokens(%q) error = %v, want usage error: %v", tt.tokens, err, tt.wantUsage)
+			}
+			if !tt.wantUsage && !errors.Is(err, tt.err) {
+				t.Fatalf("evalTokens(%q) error = %v, want %v", tt.tokens, err, tt.err)
+			}
+			if result != tt.expected {
+				t.Errorf("evalTokens(%q) = %d, want %d", tt.tokens, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCalcCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{"text", []string{"calc", "2", "+", "3", "*", "4"}, exitOK, "14\n", ""},
+		{"json", []string{"--json", "calc", "6", "/", "2"}, exitOK, `{"expression":"6 / 2","result":3}` + "\n", ""},
+		{"divide by zero", []string{"calc", "1", "/", "0"}, exitError, "", "calc: division by zero\n"},
+		{"overflow", []string{"calc", "9223372036854775807", "*", "2"}, exitError, "", "calc: integer overflow\n"},
+		{"division overflow", []string{"calc", "--", "-9223372036854775808", "/", "-1"}, exitError, "", "calc: integer overflow\n"},
+		{"bad operator", []string{"calc", "1", "^", "2"}, exitUsage, "", `unknown operator "^"`},
+		{"min", []string{"calc", "min", "3", "-1", "2"}, exitOK, "-1\n", ""},
+		{"max", []string{"calc", "max", "3", "-1", "2"}, exitOK, "3\n", ""},
+		{"avg", []string{"calc", "avg", "1", "2"}, exitOK, "1.5\n", ""},
+		{"avg whole", []string{"calc", "avg", "2", "4"}, exitOK, "3\n", ""},
+		{"max exact", []string{"calc", "max", "9223372036854775807"}, exitOK, "9223372036854775807\n", ""},
+		{"aggregate json", []string{"--json", "calc", "avg", "1", "2"}, exitOK, `{"operation":"avg","operands":[1,2],"result":1.5}` + "\n", ""},
+		{"aggregate without operands", []string{"calc", "min"}, exitUsage, "", "min needs at least one operand"},
+		{"aggregate invalid operand", []string{"calc", "avg", "1", "x"}, exitUsage, "", `invalid integer "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := run(tt.args, &out, &errOut)
+			if code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, errOut.String())
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErrOut)
+			}
+		})
+	}
+}
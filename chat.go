@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ericbfriday/claude-go-containers/claude"
+)
+
+// chatPrompt is written to stderr before each message is read.
+const chatPrompt = "you> "
+
+func chatCommand() command {
+	return command{
+		name:    "chat",
+		args:    "[--no-stream] [--system TEXT]",
+		summary: "Hold a multi-turn conversation with Claude",
+		setFlags: func(fs *flag.FlagSet) handler {
+			system := fs.String("system", "", "instruction that opens every prompt")
+			noStream := fs.Bool("no-stream", false, "print each reply only once it is complete")
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 {
+					return nil, usagef("unexpected arguments: %v", args)
+				}
+				// Replies are written as they arrive, as plain text.
+				if a.json {
+					return nil, usagef("chat cannot be combined with --json")
+				}
+				s := claude.NewSession(&claude.Client{Logger: a.logger, DryRun: a.dryRun, Metrics: a.metrics})
+				s.System = *system
+				return nil, a.chat(s, !*noStream)
+			}
+		},
+	}
+}
+
+// chat reads messages from a.in, one per line, and writes each reply to
+// a.out, as it arrives if stream is set. Lines starting with a slash are
+// session commands; see chatLine. A failed turn is reported and the
+// conversation continues; Ctrl-C while waiting for a reply abandons just
+// that turn. The loop ends on /exit or end of input.
+func (a *app) chat(s *claude.Session, stream bool) error {
+	fmt.Fprint(a.errOut, chatPrompt)
+	err := scanLines(a.in, func(line string) error {
+		defer fmt.Fprint(a.errOut, chatPrompt)
+		return a.chatLine(s, line, stream)
+	})
+	fmt.Fprintln(a.errOut)
+	if errors.Is(err, errQuit) {
+		return nil
+	}
+	return err
+}
+
+func (a *app) chatLine(s *claude.Session, line string, stream bool) error {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil
+	}
+	if strings.HasPrefix(line, "/") {
+		cmd, arg, _ := strings.Cut(line, " ")
+		arg = strings.TrimSpace(arg)
+		switch cmd {
+		case "/exit", "/quit":
+			return errQuit
+		case "/reset":
+			s.Reset()
+			fmt.Fprintln(a.errOut, "history cleared")
+		case "/save":
+			if arg == "" {
+				fmt.Fprintln(a.errOut, "usage: /save <file>")
+				return nil
+			}
+			if err := s.Save(arg); err != nil {
+				fmt.Fprintf(a.errOut, "chat: %v\n", err)
+				return nil
+			}
+			fmt.Fprintf(a.errOut, "saved %d turns to %s\n", len(s.History()), arg)
+		case "/help":
+			fmt.Fprintln(a.errOut, "Commands:")
+			fmt.Fprintln(a.errOut, "  /reset        Forget the conversation so far")
+			fmt.Fprintln(a.errOut, "  /save <file>  Write the transcript to file as JSON")
+			fmt.Fprintln(a.errOut, "  /exit         Leave the chat")
+		default:
+			fmt.Fprintf(a.errOut, "unknown command %q; type /help for a list of commands\n", cmd)
+		}
+		return nil
+	}
+
+	ctx, stop := a.toolContext()
+	defer stop()
+	w := &newlineWriter{w: a.out}
+	var err error
+	if stream {
+		_, err = s.SendStream(ctx, line, w)
+	} else {
+		var reply string
+		if reply, err = s.Send(ctx, line); err == nil {
+			_, err = io.WriteString(w, reply)
+		}
+	}
+	if ferr := w.finish(); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		fmt.Fprintf(a.errOut, "chat: %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/claude"
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestChat(t *testing.T) {
+	dir := t.TempDir()
+	// Reply with the last line of the prompt: the message itself on the
+	// first turn and "User: <message>" once there is history.
+	stub.Write(t, dir, "claude", `cat | tail -n 1`)
+	stub.PrependPath(t, dir)
+	transcript := filepath.Join(dir, "chat.json")
+
+	input := strings.Join([]string{
+		"hello",
+		"",
+		"second",
+		"/save " + transcript,
+		"/reset",
+		"third",
+		"/save",
+		"/bogus",
+		"/exit",
+		"never sent",
+	}, "\n")
+	var out, errOut bytes.Buffer
+	if code := newApp(strings.NewReader(input), &out, &errOut).run([]string{"chat"}); code != exitOK {
+		t.Fatalf("chat = %d, want %d (stderr: %s)", code, exitOK, errOut.String())
+	}
+
+	if want := "hello\nUser: second\nthird\n"; out.String() != want {
+		t.Errorf("stdout = %q, want %q", out.String(), want)
+	}
+	for _, want := range []string{"saved 2 turns to " + transcript, "history cleared", "usage: /save <file>", `unknown command "/bogus"`} {
+		if !strings.Contains(errOut.String(), want) {
+			t.Errorf("stderr = %q, want it to contain %q", errOut.String(), want)
+		}
+	}
+
+	data, err := os.ReadFile(transcript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var turns []claude.Turn
+	if err := json.Unmarshal(data, &turns); err != nil {
+		t.Fatal(err)
+	}
+	if len(turns) != 2 || turns[0].User != "hello" || turns[1].User != "second" {
+		t.Errorf("saved turns = %q, want hello and second", turns)
+	}
+}
+
+func TestChatRejectsJSON(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	stub.Write(t, dir, "claude", `touch "`+marker+`"; echo hi`)
+	stub.PrependPath(t, dir)
+
+	var out, errOut bytes.Buffer
+	if code := newApp(strings.NewReader("hello\n"), &out, &errOut).run([]string{"--json", "chat"}); code != exitUsage {
+		t.Errorf("--json chat = %d, want %d", code, exitUsage)
+	}
+	if out.Len() != 0 {
+		t.Errorf("stdout = %q, want nothing", out.String())
+	}
+	if want := "chat cannot be combined with --json"; !strings.Contains(errOut.String(), want) {
+		t.Errorf("stderr = %q, want it to contain %q", errOut.String(), want)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("claude ran despite the usage error")
+	}
+}
+
+func TestChatFailedTurnContinues(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `case "$(cat)" in *boom*) echo "overloaded" >&2; exit 1 ;; *) printf ok ;; esac`)
+	stub.PrependPath(t, dir)
+
+	for _, args := range [][]string{{"chat"}, {"chat", "--no-stream"}} {
+		var out, errOut bytes.Buffer
+		if code := newApp(strings.NewReader("boom\nhi\n"), &out, &errOut).run(args); code != exitOK {
+			t.Fatalf("%q = %d, want %d", args, code, exitOK)
+		}
+		if out.String() != "ok\n" {
+			t.Errorf("%q: stdout = %q, want the reply to the second message", args, out.String())
+		}
+		if !strings.Contains(errOut.String(), "chat: ") || !strings.Contains(errOut.String(), "overloaded") {
+			t.Errorf("%q: stderr = %q, want the failed turn reported", args, errOut.String())
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// RunBatch runs prompts with Run, at most concurrency at a time, and
+// returns the responses in the order of prompts. A concurrency below 1
+// means one at a time.
+//
+// The first failure cancels the invocations still running, whose process
+// groups are stopped as for a done context, and prompts not yet started
+// are skipped. RunBatch then returns that failure, identifying the prompt
+// by its 1-based position, and no responses. Use RunBatchBestEffort to
+// run every prompt regardless.
+func (c *Client) RunBatch(ctx context.Context, prompts []string, concurrency int) ([]string, error) {
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(max(concurrency, 1))
+	responses := make([]string, len(prompts))
+	for i, p := range prompts {
+		g.Go(func() error {
+			// Skip prompts whose turn comes after the batch is canceled.
+			if gctx.Err() != nil {
+				return nil
+			}
+			r, err := c.Run(gctx, p)
+			if err != nil {
+				return fmt.Errorf("prompt %d: %w", i+1, err)
+			}
+			responses[i] = r
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	// Prompts are also skipped if ctx is done before anything fails.
+	if ctx.Err() != nil {
+		return nil, context.Cause(ctx)
+	}
+	return responses, nil
+}
+
+// RunBatchBestEffort is like RunBatch but runs every prompt even if some
+// fail. errs[i] is the error, if any, from running prompts[i], in which
+// case responses[i] is empty. Only ctx being done stops the remaining
+// prompts, each of which then fails with its error.
+func (c *Client) RunBatchBestEffort(ctx context.Context, prompts []string, concurrency int) (responses []string, errs []error) {
+	var g errgroup.Group
+	g.SetLimit(max(concurrency, 1))
+	responses = make([]string, len(prompts))
+	errs = make([]error, len(prompts))
+	for i, p := range prompts {
+		g.Go(func() error {
+			responses[i], errs[i] = c.Run(ctx, p)
+			return nil
+		})
+	}
+	g.Wait()
+	return responses, errs
+}
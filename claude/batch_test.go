@@ -0,0 +1,111 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/exitcode"
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestRunBatch(t *testing.T) {
+	dir := t.TempDir()
+	running, peaks := filepath.Join(dir, "running"), filepath.Join(dir, "peaks")
+	if err := os.Mkdir(running, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// Earlier prompts sleep longer, so responses finish out of order. Each
+	// invocation records how many were running when it started.
+	c := &Client{BinPath: stub.Write(t, dir, "claude", `n=$(cat)
+touch "`+running+`/$$"; ls "`+running+`" | wc -l >> "`+peaks+`"
+sleep 0.$((9 - n))
+rm "`+running+`/$$"; echo "reply $n"`)}
+
+	prompts := []string{"1", "2", "3", "4", "5", "6"}
+	got, err := c.RunBatch(context.Background(), prompts, 3)
+	if err != nil {
+		t.Fatalf("RunBatch() error = %v", err)
+	}
+	want := make([]string, len(prompts))
+	for i, p := range prompts {
+		want[i] = "reply " + p + "\n"
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("RunBatch() = %q, want %q", got, want)
+	}
+
+	data, err := os.ReadFile(peaks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range strings.Fields(string(data)) {
+		if n, _ := strconv.Atoi(f); n > 3 {
+			t.Errorf("%d invocations ran at once, want at most 3", n)
+		}
+	}
+}
+
+func TestRunBatchFailFast(t *testing.T) {
+	dir := t.TempDir()
+	pidFile, late := filepath.Join(dir, "child.pid"), filepath.Join(dir, "late")
+	// The slow prompt's child only exits if its process group is killed;
+	// the failing prompt waits until it is running.
+	c := &Client{BinPath: stub.Write(t, dir, "claude", `case "$(cat)" in
+slow) sleep 30 & echo $! > "`+pidFile+`"; wait ;;
+fail) while [ ! -s "`+pidFile+`" ]; do sleep 0.01; done; echo "bad prompt" >&2; exit 3 ;;
+late) touch "`+late+`" ;;
+esac`)}
+
+	got, err := c.RunBatch(context.Background(), []string{"slow", "fail", "late"}, 2)
+	var exitErr *exitcode.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 || !strings.HasPrefix(err.Error(), "prompt 2: ") {
+		t.Fatalf("RunBatch() error = %v, want prompt 2's exit status 3", err)
+	}
+	if got != nil {
+		t.Errorf("RunBatch() = %q, want no responses", got)
+	}
+	if pid := stub.WaitPID(t, pidFile); !stub.WaitExit(pid) {
+		t.Errorf("slow prompt's child %d still running after the batch failed", pid)
+	}
+	if _, err := os.Stat(late); err == nil {
+		t.Error("prompt queued behind the failure was run")
+	}
+}
+
+func TestRunBatchCanceled(t *testing.T) {
+	dir := t.TempDir()
+	c := &Client{BinPath: stub.Write(t, dir, "claude", `cat`)}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.RunBatch(ctx, []string{"a", "b"}, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("RunBatch() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestRunBatchBestEffort(t *testing.T) {
+	dir := t.TempDir()
+	c := &Client{BinPath: stub.Write(t, dir, "claude", `p=$(cat); case "$p" in fail*) exit 1 ;; esac; echo "reply $p"`)}
+
+	prompts := []string{"a", "fail 1", "b", "fail 2", "c"}
+	got, errs := c.RunBatchBestEffort(context.Background(), prompts, 2)
+	for i, p := range prompts {
+		wantFail := strings.HasPrefix(p, "fail")
+		if (errs[i] != nil) != wantFail {
+			t.Errorf("prompt %q: error = %v, want failure: %v", p, errs[i], wantFail)
+		}
+		want := ""
+		if !wantFail {
+			want = fmt.Sprintf("reply %s\n", p)
+		}
+		if got[i] != want {
+			t.Errorf("prompt %q: response = %q, want %q", p, got[i], want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package claude
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ericbfriday/claude-go-containers/internal/atomicfile"
+)
+
+// Cache stores responses keyed by CacheKey. Implementations must be safe
+// for concurrent use.
+type Cache interface {
+	Get(key string) (string, bool)
+	Set(key, value string)
+}
+
+// CacheKey returns the key a Client uses to cache the response to prompt:
+// the hex-encoded SHA-256 of the prompt.
+func CacheKey(prompt string) string {
+	sum := sha256.Sum256([]byte(prompt))
+	return hex.EncodeToString(sum[:])
+}
+
+// LRUCache is an in-memory Cache holding at most a fixed number of
+// entries, evicting the least recently used when full.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key, value string
+}
+
+// NewLRUCache returns an LRUCache holding up to size entries. A size below
+// 1 is treated as 1.
+func NewLRUCache(size int) *LRUCache {
+	return &LRUCache{
+		size:    max(size, 1),
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored under key and marks it as recently used.
+func (c *LRUCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*lruEntry).value, true
+}
+
+// Set stores value under key, evicting the least recently used entry if
+// the cache is full.
+func (c *LRUCache) Set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruEntry).value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of entries in the cache.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// DirCache is a Cache storing one file per entry in Dir, so responses
+// survive between runs. Dir is created on the first Set. Write errors are
+// ignored; a failed Set only means a later Get misses.
+type DirCache struct {
+	Dir string
+}
+
+// Get returns the contents of the file for key.
+func (c DirCache) Get(key string) (string, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Set writes value to the file for key with atomicfile, so concurrent
+// readers never see a partial entry.
+func (c DirCache) Set(key, value string) {
+	f, err := atomicfile.Create(c.path(key))
+	if err != nil {
+		return
+	}
+	if _, err := f.WriteString(value); err != nil {
+		f.Abort()
+		return
+	}
+	f.Commit()
+}
+
+// path returns the file for key. Only the base name is used so a key can
+// never escape Dir.
+func (c DirCache) path(key string) string {
+	return filepath.Join(c.Dir, filepath.Base(key))
+}
@@ -0,0 +1,88 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheKey(t *testing.T) {
+	// echo -n hi | sha256sum
+	const want = "8f434346648f6b96df89dda901c5176b10a6d83961dd3c1ac88b59b2dc327aa4"
+	if got := CacheKey("hi"); got != want {
+		t.Errorf("CacheKey(%q) = %s, want %s", "hi", got, want)
+	}
+	if CacheKey("hi") == CacheKey("hi ") {
+		t.Error("CacheKey() is the same for different prompts")
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	c := NewLRUCache(2)
+	c.Set("a", "1")
+	c.Set("b", "2")
+	if got, ok := c.Get("a"); !ok || got != "1" {
+		t.Errorf("Get(a) = %q, %v, want %q, true", got, ok, "1")
+	}
+
+	// "b" is now least recently used and is evicted.
+	c.Set("c", "3")
+	if _, ok := c.Get("b"); ok {
+		t.Error("Get(b) hit after eviction")
+	}
+	for key, want := range map[string]string{"a": "1", "c": "3"} {
+		if got, ok := c.Get(key); !ok || got != want {
+			t.Errorf("Get(%s) = %q, %v, want %q, true", key, got, ok, want)
+		}
+	}
+
+	c.Set("a", "updated")
+	if got, _ := c.Get("a"); got != "updated" {
+		t.Errorf("Get(a) after overwrite = %q, want %q", got, "updated")
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestLRUCacheMinimumSize(t *testing.T) {
+	c := NewLRUCache(0)
+	c.Set("a", "1")
+	if got, ok := c.Get("a"); !ok || got != "1" {
+		t.Errorf("Get(a) = %q, %v, want %q, true", got, ok, "1")
+	}
+}
+
+func TestDirCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "cache")
+	c := DirCache{Dir: dir}
+
+	if _, ok := c.Get("k"); ok {
+		t.Error("Get() hit on an empty cache")
+	}
+	c.Set("k", "response\n")
+	if got, ok := c.Get("k"); !ok || got != "response\n" {
+		t.Errorf("Get(k) = %q, %v, want %q, true", got, ok, "response\n")
+	}
+	// A second cache on the same directory sees the entry.
+	if got, ok := (DirCache{Dir: dir}).Get("k"); !ok || got != "response\n" {
+		t.Errorf("Get(k) from a new DirCache = %q, %v", got, ok)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "k" {
+		t.Errorf("cache dir holds %v, want only the entry file", entries)
+	}
+}
+
+func TestDirCacheKeyCannotEscapeDir(t *testing.T) {
+	root := t.TempDir()
+	c := DirCache{Dir: filepath.Join(root, "cache")}
+	c.Set("../escaped", "x")
+	if _, err := os.Stat(filepath.Join(root, "escaped")); err == nil {
+		t.Error("Set() wrote outside the cache directory")
+	}
+}
@@ -0,0 +1,265 @@
+// Package claude runs the Claude CLI as a subprocess so Go programs can
+// send it prompts and read back its responses.
+package claude
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/exitcode"
+	"github.com/ericbfriday/claude-go-containers/internal/argv"
+	"github.com/ericbfriday/claude-go-containers/internal/procgroup"
+	"github.com/ericbfriday/claude-go-containers/internal/shellquote"
+	"github.com/ericbfriday/claude-go-containers/metrics"
+	"github.com/ericbfriday/claude-go-containers/retry"
+)
+
+// DefaultBin is the executable looked up on PATH when Client.BinPath is empty.
+const DefaultBin = "claude"
+
+// DefaultArgs put the CLI in non-interactive print mode, reading the prompt
+// from standard input and writing the response to standard output.
+var DefaultArgs = []string{"--print"}
+
+// Client invokes the Claude CLI. The zero value is ready to use. A Client
+// must not be copied after first use; its methods are safe for concurrent
+// use as long as the Cache is.
+type Client struct {
+	// BinPath is the executable to run. If empty, DefaultBin is looked up
+	// on PATH.
+	BinPath string
+	// Args are passed to the executable. If nil, DefaultArgs are used.
+	Args []string
+	// Logger receives a debug record for every invocation and an error
+	// record for every failure. If nil, nothing is logged.
+	Logger *slog.Logger
+	// RetryPolicy, if set, retries failed invocations with exponential
+	// backoff. Failures that retrying cannot fix, such as a missing
+	// executable or a done context, are returned immediately.
+	RetryPolicy *retry.Policy
+	// Cache, if set, stores successful responses under CacheKey(prompt)
+	// and answers repeated prompts without invoking the CLI.
+	Cache Cache
+	// Limiter, if set, paces invocations, including retries; share one
+	// between Clients to limit them together. It takes precedence over
+	// RequestsPerMinute.
+	Limiter *Limiter
+	// RequestsPerMinute, if positive and Limiter is nil, limits this
+	// Client alone to that many invocations per minute.
+	RequestsPerMinute int
+
+	// DryRun, if set, makes Run write the shell command line it would
+	// execute to DryRun and return an empty response without running
+	// anything. Dry-run responses are never cached.
+	DryRun io.Writer
+	// Metrics, if set, records every invocation of the CLI, its latency
+	// and whether it failed, under the tool label "claude".
+	Metrics *metrics.Registry
+
+	initOnce   sync.Once
+	ownLimiter *Limiter
+	dryRunMu   sync.Mutex // serializes writes to DryRun
+}
+
+// limiter returns the Limiter pacing this Client, or nil if it is not rate
+// limited.
+func (c *Client) limiter() *Limiter {
+	if c.Limiter != nil {
+		return c.Limiter
+	}
+	c.initOnce.Do(func() {
+		if c.RequestsPerMinute > 0 {
+			c.ownLimiter = NewLimiter(c.RequestsPerMinute)
+		}
+	})
+	return c.ownLimiter
+}
+
+func (c *Client) logger() *slog.Logger {
+	if c.Logger == nil {
+		return slog.New(slog.DiscardHandler)
+	}
+	return c.Logger
+}
+
+// Run sends prompt to the CLI on standard input and returns what it writes
+// to standard output. If ctx is done before the subprocess exits, its
+// whole process group is sent SIGTERM and, after procgroup.DefaultGrace,
+// SIGKILL, so no child processes are left behind, and the error wraps
+// context.Cause(ctx). A non-zero exit status
+// is returned as an *exitcode.ExitError that includes the CLI's standard
+// error output.
+func (c *Client) Run(ctx context.Context, prompt string) (string, error) {
+	key, cached, ok := c.cached(prompt)
+	if ok {
+		return cached, nil
+	}
+
+	var out bytes.Buffer
+	err := c.RetryPolicy.Do(ctx, func() error {
+		out.Reset()
+		return c.run(ctx, prompt, &out)
+	})
+	if err != nil {
+		return "", err
+	}
+	c.store(key, out.String())
+	return out.String(), nil
+}
+
+// RunStream is like Run but copies the CLI's standard output to out as it
+// arrives instead of returning it, so long responses can be shown as they
+// are generated. A cached response is written to out in one piece.
+//
+// An attempt is only retried if it failed before writing anything, since
+// out cannot take back what it has been given.
+func (c *Client) RunStream(ctx context.Context, prompt string, out io.Writer) error {
+	key, cached, ok := c.cached(prompt)
+	if ok {
+		_, err := io.WriteString(out, cached)
+		return err
+	}
+
+	w := &countingWriter{w: out}
+	var saved bytes.Buffer
+	var dst io.Writer = w
+	if c.Cache != nil {
+		dst = io.MultiWriter(w, &saved)
+	}
+	err := c.RetryPolicy.Do(ctx, func() error {
+		err := c.run(ctx, prompt, dst)
+		if err != nil && w.n > 0 {
+			return retry.Permanent(err)
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	c.store(key, saved.String())
+	return nil
+}
+
+// cached looks prompt up in c.Cache, returning its key for a later store.
+func (c *Client) cached(prompt string) (key, out string, ok bool) {
+	if c.Cache == nil {
+		return "", "", false
+	}
+	key = CacheKey(prompt)
+	if out, ok = c.Cache.Get(key); ok {
+		c.logger().Debug("cache hit", "key", key)
+	}
+	return key, out, ok
+}
+
+// store caches a successful response. Dry runs produce no response and
+// are never cached.
+func (c *Client) store(key, out string) {
+	if c.Cache != nil && c.DryRun == nil {
+		c.Cache.Set(key, out)
+	}
+}
+
+// run makes a single invocation, copying its standard output to stdout.
+// Errors that retrying cannot fix are marked with retry.Permanent.
+func (c *Client) run(ctx context.Context, prompt string, stdout io.Writer) error {
+	bin := c.BinPath
+	if bin == "" {
+		bin = DefaultBin
+	}
+	args := c.Args
+	if args == nil {
+		args = DefaultArgs
+	}
+	// The prompt goes to standard input rather than into argv, where it
+	// would be visible to every user in the process list.
+	cmdline, err := argv.New(bin, args...).Build()
+	if err != nil {
+		return retry.Permanent(fmt.Errorf("claude: %w", err))
+	}
+	if c.DryRun != nil {
+		return c.dryRun(cmdline, prompt)
+	}
+
+	if l := c.limiter(); l != nil {
+		if err := l.Wait(ctx); err != nil {
+			return retry.Permanent(fmt.Errorf("claude: %w", err))
+		}
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
+	cmd.Stdin = strings.NewReader(prompt)
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+	procgroup.Configure(cmd, procgroup.DefaultGrace)
+
+	log := c.logger()
+	start := time.Now()
+	err = cmd.Run()
+	elapsed := time.Since(start)
+	c.Metrics.ObserveInvocation("claude", elapsed, err)
+	log.DebugContext(ctx, "exec", "bin", cmd.Path, "args", args, "duration", elapsed)
+	if err != nil {
+		log.ErrorContext(ctx, "claude invocation failed", "bin", cmd.Path, "args", args, "err", err, "stderr", strings.TrimSpace(stderr.String()))
+		if ctx.Err() != nil {
+			return retry.Permanent(fmt.Errorf("claude: %w", context.Cause(ctx)))
+		}
+		if errors.Is(err, exec.ErrNotFound) || errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrPermission) {
+			return retry.Permanent(fmt.Errorf("claude: %w", err))
+		}
+		msg := strings.TrimSpace(stderr.String())
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return &exitcode.ExitError{Tool: "claude", Code: exitErr.ExitCode(), Stderr: msg, Err: err}
+		}
+		if msg != "" {
+			return fmt.Errorf("claude: %w: %s", err, msg)
+		}
+		return fmt.Errorf("claude: %w", err)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// dryRun writes the command line that run would execute to c.DryRun, with
+// the prompt piped in on standard input. The executable, cmdline[0], is
+// resolved on PATH so the line shows exactly which one would run; if it
+// cannot be found the name is written as given.
+func (c *Client) dryRun(cmdline []string, prompt string) error {
+	if path, err := exec.LookPath(cmdline[0]); err == nil {
+		cmdline[0] = path
+	}
+	line := shellquote.Join(cmdline...)
+	if prompt != "" {
+		line = "printf '%s' " + shellquote.Quote(prompt) + " | " + line
+	}
+	c.logger().Info("dry run", "command", line)
+	c.dryRunMu.Lock()
+	_, err := fmt.Fprintln(c.DryRun, line)
+	c.dryRunMu.Unlock()
+	if err != nil {
+		return retry.Permanent(fmt.Errorf("claude: %w", err))
+	}
+	return nil
+}
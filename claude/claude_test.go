@@ -0,0 +1,458 @@
+package claude
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/exitcode"
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+	"github.com/ericbfriday/claude-go-containers/metrics"
+	"github.com/ericbfriday/claude-go-containers/retry"
+)
+
+func TestRunPassesPromptOnStdin(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `printf 'args:%s\n' "$*"; printf 'prompt:'; cat`)
+	stub.PrependPath(t, dir)
+
+	var c Client
+	got, err := c.Run(context.Background(), "Explain goroutines\nin two lines")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	want := "args:--print\nprompt:Explain goroutines\nin two lines"
+	if got != want {
+		t.Errorf("Run() = %q, want %q", got, want)
+	}
+}
+
+func TestRunPromptRoundTrips(t *testing.T) {
+	dir := t.TempDir()
+	// The stub echoes its stdin and records its argv one argument per line,
+	// so the test sees both what the prompt became and where it went.
+	argsFile := filepath.Join(dir, "args")
+	c := Client{BinPath: stub.Write(t, dir, "claude", `printf '%s\n' "$@" > "`+argsFile+`"; cat`)}
+	prompt := `say "hello world" and it's done; echo $HOME $(id) \n`
+	got, err := c.Run(context.Background(), prompt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != prompt {
+		t.Errorf("Run() = %q, want the prompt back intact: %q", got, prompt)
+	}
+	if args, _ := os.ReadFile(argsFile); string(args) != "--print\n" {
+		t.Errorf("argv = %q, want only --print and the prompt kept out of the process list", args)
+	}
+}
+
+func TestRunRejectsNULArgument(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	c := Client{
+		BinPath:     stub.Write(t, dir, "claude", `touch "`+marker+`"`),
+		Args:        []string{"--print", "--model", "a\x00b"},
+		RetryPolicy: &retry.Policy{Attempts: 3, Backoff: time.Millisecond},
+	}
+	if _, err := c.Run(context.Background(), "hi"); err == nil || !strings.Contains(err.Error(), "NUL") {
+		t.Fatalf("Run() error = %v, want a NUL byte error", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("the CLI was started despite an invalid argument")
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		script  string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{"custom args", `echo "$@"`, []string{"-p", "--model", "x"}, "-p --model x\n", ""},
+		{"empty response", `cat >/dev/null`, nil, "", ""},
+		{"non-zero exit includes stderr", `echo "rate limited" >&2; exit 3`, nil, "", "exit status 3: rate limited"},
+		{"non-zero exit without stderr", `exit 1`, nil, "", "exit status 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Client{BinPath: stub.Write(t, dir, strings.ReplaceAll(tt.name, " ", "-"), tt.script), Args: tt.args}
+			got, err := c.Run(context.Background(), "hi")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Run() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Run() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// notifyWriter collects writes and closes seen once they contain want.
+type notifyWriter struct {
+	want string
+	seen chan struct{}
+	b    strings.Builder
+}
+
+func (w *notifyWriter) Write(p []byte) (int, error) {
+	had := strings.Contains(w.b.String(), w.want)
+	w.b.Write(p)
+	if !had && strings.Contains(w.b.String(), w.want) {
+		close(w.seen)
+	}
+	return len(p), nil
+}
+
+func TestRunStreamWritesIncrementally(t *testing.T) {
+	dir := t.TempDir()
+	release := filepath.Join(dir, "release")
+	// The second line is only written once the first has been seen, so a
+	// buffered implementation fails instead of passing by timing.
+	c := Client{BinPath: stub.Write(t, dir, "claude", `cat >/dev/null; echo first
+i=0; while [ ! -e "`+release+`" ] && [ $i -lt 500 ]; do sleep 0.01; i=$((i+1)); done
+[ -e "`+release+`" ] || { echo "first line was not streamed" >&2; exit 1; }
+echo second`)}
+
+	out := &notifyWriter{want: "first\n", seen: make(chan struct{})}
+	go func() {
+		<-out.seen
+		os.WriteFile(release, nil, 0o644)
+	}()
+	if err := c.RunStream(context.Background(), "hi", out); err != nil {
+		t.Fatalf("RunStream() error = %v", err)
+	}
+	if got := out.b.String(); got != "first\nsecond\n" {
+		t.Errorf("streamed %q, want both lines", got)
+	}
+}
+
+func TestRunStream(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls")
+	tests := []struct {
+		name      string
+		script    string
+		want      string
+		wantErr   string
+		wantCalls int
+	}{
+		{"success", `cat`, "hi", "", 1},
+		{"failure keeps partial output and stderr", `echo partial; echo "overloaded" >&2; exit 1`, "partial\n", "exit status 1: overloaded", 1},
+		{"failure before output is retried", `echo "overloaded" >&2; exit 1`, "", "overloaded", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(counter)
+			c := Client{
+				BinPath:     stub.Write(t, dir, strings.ReplaceAll(tt.name, " ", "-"), `echo x >>"`+counter+`"; `+tt.script),
+				RetryPolicy: &retry.Policy{Attempts: 3, Backoff: time.Millisecond},
+			}
+			var out strings.Builder
+			err := c.RunStream(context.Background(), "hi", &out)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("RunStream() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Errorf("RunStream() error = %v", err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("streamed %q, want %q", out.String(), tt.want)
+			}
+			data, _ := os.ReadFile(counter)
+			if n := strings.Count(string(data), "\n"); n != tt.wantCalls {
+				t.Errorf("invoked %d times, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRunStreamCache(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls")
+	c := Client{
+		BinPath: stub.Write(t, dir, "claude", `echo x >>"`+counter+`"; echo "you said: $(cat)"`),
+		Cache:   NewLRUCache(4),
+	}
+	for i := range 2 {
+		var out strings.Builder
+		if err := c.RunStream(context.Background(), "hi", &out); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != "you said: hi\n" {
+			t.Errorf("call %d streamed %q", i+1, out.String())
+		}
+	}
+	if got, _ := c.Run(context.Background(), "hi"); got != "you said: hi\n" {
+		t.Errorf("Run() after RunStream = %q, want the cached response", got)
+	}
+	data, _ := os.ReadFile(counter)
+	if n := strings.Count(string(data), "\n"); n != 1 {
+		t.Errorf("invoked %d times, want 1", n)
+	}
+}
+
+func TestRunExitError(t *testing.T) {
+	c := Client{BinPath: stub.Write(t, t.TempDir(), "claude", `echo "bad flag" >&2; exit 42`)}
+	_, err := c.Run(context.Background(), "hi")
+	var exitErr *exitcode.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("Run() error = %v, want an *exitcode.ExitError", err)
+	}
+	if exitErr.Tool != "claude" || exitErr.Code != 42 || exitErr.Stderr != "bad flag" {
+		t.Errorf("ExitError = %+v, want claude, 42 and the stderr", exitErr)
+	}
+}
+
+func TestRunMissingBinary(t *testing.T) {
+	c := Client{BinPath: "/nonexistent/claude"}
+	if _, err := c.Run(context.Background(), "hi"); err == nil {
+		t.Fatal("Run() error = nil, want error")
+	}
+}
+
+func TestRunRetries(t *testing.T) {
+	dir := t.TempDir()
+	counter := dir + "/calls"
+	// The stub fails until it has been invoked three times.
+	script := `echo x >>"` + counter + `"
+if [ "$(wc -l <"` + counter + `")" -lt 3 ]; then echo "overloaded" >&2; exit 1; fi
+echo ok`
+	bin := stub.Write(t, dir, "claude", script)
+	calls := func() int {
+		data, _ := os.ReadFile(counter)
+		return strings.Count(string(data), "\n")
+	}
+
+	tests := []struct {
+		name      string
+		attempts  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"succeeds within attempts", 3, 3, false},
+		{"gives up after attempts", 2, 2, true},
+		{"no policy means one attempt", 0, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(counter)
+			c := Client{BinPath: bin}
+			if tt.attempts > 0 {
+				c.RetryPolicy = &retry.Policy{Attempts: tt.attempts, Backoff: time.Millisecond}
+			}
+			got, err := c.Run(context.Background(), "hi")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != "ok\n" {
+				t.Errorf("Run() = %q, want %q", got, "ok\n")
+			}
+			if n := calls(); n != tt.wantCalls {
+				t.Errorf("binary invoked %d times, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRunMissingBinaryIsNotRetried(t *testing.T) {
+	c := Client{
+		BinPath:     "/nonexistent/claude",
+		RetryPolicy: &retry.Policy{Attempts: 5, Backoff: time.Hour},
+	}
+	start := time.Now()
+	_, err := c.Run(context.Background(), "hi")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Run() error = %v, want %v", err, fs.ErrNotExist)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() took %v, want it to give up without backing off", elapsed)
+	}
+}
+
+func TestRunCache(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls")
+	bin := stub.Write(t, dir, "claude", `echo x >>"`+counter+`"; echo "answer to $(cat)"`)
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := Client{BinPath: bin, Cache: NewLRUCache(8), Logger: logger}
+
+	for range 3 {
+		got, err := c.Run(context.Background(), "hi")
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if got != "answer to hi\n" {
+			t.Errorf("Run() = %q, want %q", got, "answer to hi\n")
+		}
+	}
+	if _, err := c.Run(context.Background(), "other"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(counter)
+	if n := strings.Count(string(data), "\n"); n != 2 {
+		t.Errorf("binary invoked %d times, want 2 (once per distinct prompt)", n)
+	}
+	if n := strings.Count(buf.String(), "msg=\"cache hit\""); n != 2 {
+		t.Errorf("logged %d cache hits, want 2:\n%s", n, buf.String())
+	}
+}
+
+func TestRunCacheHitDoesNotInvokeBinary(t *testing.T) {
+	cache := NewLRUCache(1)
+	cache.Set(CacheKey("hi"), "cached")
+	c := Client{BinPath: "/nonexistent/claude", Cache: cache}
+
+	got, err := c.Run(context.Background(), "hi")
+	if err != nil {
+		t.Fatalf("Run() error = %v, want the cached response", err)
+	}
+	if got != "cached" {
+		t.Errorf("Run() = %q, want %q", got, "cached")
+	}
+}
+
+func TestRunDoesNotCacheFailures(t *testing.T) {
+	cache := NewLRUCache(1)
+	c := Client{BinPath: stub.Write(t, t.TempDir(), "claude", `exit 1`), Cache: cache}
+	if _, err := c.Run(context.Background(), "hi"); err == nil {
+		t.Fatal("Run() error = nil, want error")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("cache holds %d entries after a failure, want 0", cache.Len())
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	bin := stub.Write(t, dir, "claude", `touch "`+marker+`"; printf 'args:%s prompt:' "$*"; cat`)
+	prompt := "what's $HOME?\nline two"
+
+	var out bytes.Buffer
+	cache := NewLRUCache(1)
+	c := Client{BinPath: bin, Args: []string{"--print", "--model", "a b"}, DryRun: &out, Cache: cache}
+	got, err := c.Run(context.Background(), prompt)
+	if err != nil || got != "" {
+		t.Fatalf("Run() = %q, %v, want empty response and no error", got, err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("binary was executed in dry-run mode")
+	}
+	if cache.Len() != 0 {
+		t.Error("dry-run response was cached")
+	}
+
+	// The printed line runs the same command when pasted into a shell.
+	line := strings.TrimSuffix(out.String(), "\n")
+	if !strings.Contains(line, "| "+bin+" --print --model 'a b'") {
+		t.Errorf("dry-run output = %q, want it to pipe into %s", line, bin)
+	}
+	shellOut, err := exec.Command("sh", "-c", line).Output()
+	if err != nil {
+		t.Fatalf("running %q: %v", line, err)
+	}
+	if want := "args:--print --model a b prompt:" + prompt; string(shellOut) != want {
+		t.Errorf("pasted command printed %q, want %q", shellOut, want)
+	}
+}
+
+func TestRunMetrics(t *testing.T) {
+	dir := t.TempDir()
+	reg := metrics.NewRegistry()
+	ok := Client{BinPath: stub.Write(t, dir, "ok", `cat`), Metrics: reg}
+	fail := Client{BinPath: stub.Write(t, dir, "fail", `exit 1`), Metrics: reg}
+	dry := Client{BinPath: stub.Write(t, dir, "dry", `cat`), Metrics: reg, DryRun: io.Discard}
+
+	ok.Run(context.Background(), "hi")
+	ok.Run(context.Background(), "hi")
+	fail.Run(context.Background(), "hi")
+	dry.Run(context.Background(), "hi")
+
+	if n := reg.Counter(metrics.ToolInvocationsTotal, "", "tool", "claude").Value(); n != 3 {
+		t.Errorf("invocations = %d, want 3", n)
+	}
+	if n := reg.Counter(metrics.ToolErrorsTotal, "", "tool", "claude").Value(); n != 1 {
+		t.Errorf("errors = %d, want 1", n)
+	}
+	if n := reg.Histogram(metrics.ToolDurationSeconds, "", nil, "tool", "claude").Count(); n != 3 {
+		t.Errorf("latency observations = %d, want 3", n)
+	}
+}
+
+func TestRunHonorsContextCancellation(t *testing.T) {
+	c := Client{BinPath: stub.Write(t, t.TempDir(), "claude", `exec sleep 30`)}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.Run(ctx, "hi")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Run() took %v after cancellation", elapsed)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		t.Errorf("Run() error wraps %v, want the context error", exitErr)
+	}
+}
+
+func TestRunLogs(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	ok := Client{BinPath: stub.Write(t, dir, "ok", `cat`), Logger: logger}
+	if _, err := ok.Run(context.Background(), "hi"); err != nil {
+		t.Fatal(err)
+	}
+	fail := Client{BinPath: stub.Write(t, dir, "fail", `echo boom >&2; exit 2`), Logger: logger}
+	if _, err := fail.Run(context.Background(), "hi"); err == nil {
+		t.Fatal("Run() error = nil, want error")
+	}
+
+	var records []map[string]any
+	dec := json.NewDecoder(&buf)
+	for dec.More() {
+		var rec map[string]any
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		records = append(records, rec)
+	}
+	if len(records) != 3 {
+		t.Fatalf("got %d log records, want 3: %v", len(records), records)
+	}
+	if records[0]["level"] != "DEBUG" || records[0]["bin"] != ok.BinPath || records[0]["duration"] == nil {
+		t.Errorf("first record = %v, want debug exec record for %s", records[0], ok.BinPath)
+	}
+	if records[2]["level"] != "ERROR" || records[2]["stderr"] != "boom" {
+		t.Errorf("last record = %v, want error record with stderr", records[2])
+	}
+}
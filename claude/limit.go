@@ -0,0 +1,52 @@
+package claude
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter paces invocations of the CLI with a token bucket. A single
+// Limiter may be shared by any number of Clients and goroutines, which then
+// coordinate to stay under one combined rate.
+type Limiter struct {
+	l *rate.Limiter
+}
+
+// NewLimiter returns a Limiter allowing requestsPerMinute invocations per
+// minute, spaced evenly rather than in bursts. A requestsPerMinute below 1
+// allows every invocation immediately.
+func NewLimiter(requestsPerMinute int) *Limiter {
+	if requestsPerMinute < 1 {
+		return &Limiter{l: rate.NewLimiter(rate.Inf, 1)}
+	}
+	return &Limiter{l: rate.NewLimiter(rate.Every(time.Minute/time.Duration(requestsPerMinute)), 1)}
+}
+
+// Wait blocks until an invocation is allowed. If ctx has a deadline that
+// would pass before then, Wait returns an error wrapping
+// context.DeadlineExceeded right away instead of sleeping until it does.
+// If ctx is done while waiting, Wait returns ctx.Err().
+func (l *Limiter) Wait(ctx context.Context) error {
+	r := l.l.Reserve()
+	delay := r.Delay()
+	if delay == 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		r.Cancel()
+		return fmt.Errorf("rate limit wait of %v: %w", delay.Round(time.Millisecond), context.DeadlineExceeded)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
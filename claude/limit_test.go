@@ -0,0 +1,116 @@
+package claude
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestLimiterSpacesRequests(t *testing.T) {
+	l := NewLimiter(600) // one every 100ms
+	start := time.Now()
+	for range 3 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("3 waits took %v, want at least 200ms", elapsed)
+	}
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := NewLimiter(0)
+	start := time.Now()
+	for range 100 {
+		if err := l.Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("unlimited waits took %v", elapsed)
+	}
+}
+
+func TestLimiterWaitWouldExceedDeadline(t *testing.T) {
+	l := NewLimiter(1) // one a minute
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	err := l.Wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Wait() took %v, want it to fail without waiting for the deadline", elapsed)
+	}
+}
+
+func TestLimiterWaitCanceled(t *testing.T) {
+	l := NewLimiter(1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestRunSharedLimiter(t *testing.T) {
+	dir := t.TempDir()
+	bin := stub.Write(t, dir, "claude", `cat`)
+	shared := NewLimiter(600)
+	clients := []*Client{
+		{BinPath: bin, Limiter: shared},
+		{BinPath: bin, Limiter: shared},
+	}
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := clients[i%2].Run(context.Background(), "hi"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if elapsed := time.Since(start); elapsed < 290*time.Millisecond {
+		t.Errorf("4 runs across 2 clients took %v, want at least 300ms from the shared limit", elapsed)
+	}
+}
+
+func TestRunRequestsPerMinute(t *testing.T) {
+	dir := t.TempDir()
+	counter := filepath.Join(dir, "calls")
+	c := Client{BinPath: stub.Write(t, dir, "claude", `echo x >>"`+counter+`"`), RequestsPerMinute: 1}
+
+	if _, err := c.Run(context.Background(), "first"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := c.Run(ctx, "second"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	data, _ := os.ReadFile(counter)
+	if n := strings.Count(string(data), "\n"); n != 1 {
+		t.Errorf("binary invoked %d times, want 1", n)
+	}
+}
@@ -0,0 +1,31 @@
+package claude
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestRunCancelKillsProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "child.pid")
+	c := Client{BinPath: stub.Write(t, dir, "claude", `sleep 30 & echo $! > "`+pidFile+`"; wait`)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Run(ctx, "hi")
+		done <- err
+	}()
+	pid := stub.WaitPID(t, pidFile)
+
+	cancel()
+	if err := <-done; err == nil {
+		t.Fatal("Run() error = nil after cancellation")
+	}
+	if !stub.WaitExit(pid) {
+		t.Errorf("child process %d still running after cancellation", pid)
+	}
+}
@@ -0,0 +1,143 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/ericbfriday/claude-go-containers/internal/atomicfile"
+	"github.com/ericbfriday/claude-go-containers/prompt"
+)
+
+// Turn is one exchange in a Session.
+type Turn struct {
+	User      string `json:"user"`
+	Assistant string `json:"assistant"`
+}
+
+// Session is a multi-turn conversation. The CLI keeps no state between
+// invocations, so every Send replays the whole transcript before the new
+// message.
+//
+// A Session is safe for concurrent use. Sends are serialized so each one
+// sees the turns before it, while History and Reset never wait for a Send
+// in progress.
+type Session struct {
+	Client *Client
+	// System, if set, opens every prompt, as with ask --system.
+	System string
+
+	send sync.Mutex // held for the duration of a Send
+
+	mu    sync.Mutex // guards the fields below
+	turns []Turn
+	gen   int // incremented by Reset
+}
+
+// NewSession returns an empty Session sending prompts through c.
+func NewSession(c *Client) *Session {
+	return &Session{Client: c}
+}
+
+// Send sends msg with the transcript so far and returns Claude's reply.
+// The turn is added to the history only once the reply has arrived: if
+// Send fails, including because ctx is done, the history is unchanged, and
+// a turn in flight when Reset is called is discarded.
+func (s *Session) Send(ctx context.Context, msg string) (string, error) {
+	return s.do(msg, func(p string) (string, error) {
+		return s.Client.Run(ctx, p)
+	})
+}
+
+// SendStream is like Send but also copies the reply to out as it arrives,
+// using Client.RunStream.
+func (s *Session) SendStream(ctx context.Context, msg string, out io.Writer) (string, error) {
+	return s.do(msg, func(p string) (string, error) {
+		var reply strings.Builder
+		err := s.Client.RunStream(ctx, p, io.MultiWriter(out, &reply))
+		return reply.String(), err
+	})
+}
+
+// do sends msg with the transcript so far through run and records the
+// turn if it succeeds.
+func (s *Session) do(msg string, run func(prompt string) (string, error)) (string, error) {
+	s.send.Lock()
+	defer s.send.Unlock()
+
+	s.mu.Lock()
+	history, gen := s.turns, s.gen
+	s.mu.Unlock()
+
+	p, err := new(prompt.Builder).WithSystem(s.System).WithText(transcript(history, msg)).Build()
+	if err != nil {
+		return "", err
+	}
+	reply, err := run(p)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.gen == gen {
+		s.turns = append(s.turns, Turn{User: msg, Assistant: reply})
+	}
+	return reply, nil
+}
+
+// History returns a copy of the turns so far, oldest first.
+func (s *Session) History() []Turn {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Turn(nil), s.turns...)
+}
+
+// Reset clears the history.
+func (s *Session) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.turns = nil
+	s.gen++
+}
+
+// Save writes the history to path as a JSON array of turns, creating its
+// directory if necessary. The file is replaced atomically, so an
+// interrupted save leaves any previous transcript intact.
+func (s *Session) Save(path string) error {
+	turns := s.History()
+	if turns == nil {
+		turns = []Turn{}
+	}
+	data, err := json.MarshalIndent(turns, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := atomicfile.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Abort()
+		return err
+	}
+	return f.Commit()
+}
+
+// transcript formats the earlier turns followed by msg. Without history
+// it is msg alone, so a one-turn session sends the same prompt as ask.
+func transcript(history []Turn, msg string) string {
+	if len(history) == 0 {
+		return msg
+	}
+	var b strings.Builder
+	b.WriteString("Continue this conversation.\n")
+	for _, t := range history {
+		b.WriteString("\nUser: " + strings.TrimSpace(t.User) + "\n")
+		b.WriteString("Assistant: " + strings.TrimSpace(t.Assistant) + "\n")
+	}
+	b.WriteString("\nUser: " + strings.TrimSpace(msg))
+	return b.String()
+}
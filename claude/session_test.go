@@ -0,0 +1,170 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestSessionSend(t *testing.T) {
+	dir := t.TempDir()
+	// The stub replies with the prompt it was given, so each reply shows
+	// what was sent.
+	c := &Client{BinPath: stub.Write(t, dir, "claude", `cat`)}
+	s := NewSession(c)
+	s.System = "Be brief."
+
+	steps := []struct {
+		msg  string
+		want string
+	}{
+		{"hi", "Be brief.\n\nhi"},
+		{"and again", "Be brief.\n\nContinue this conversation.\n\nUser: hi\nAssistant: Be brief.\n\nhi\n\nUser: and again"},
+	}
+	for _, st := range steps {
+		got, err := s.Send(context.Background(), st.msg)
+		if err != nil {
+			t.Fatalf("Send(%q) error = %v", st.msg, err)
+		}
+		if got != st.want {
+			t.Errorf("Send(%q) sent %q, want %q", st.msg, got, st.want)
+		}
+	}
+
+	want := []Turn{{User: "hi", Assistant: steps[0].want}, {User: "and again", Assistant: steps[1].want}}
+	if got := s.History(); !reflect.DeepEqual(got, want) {
+		t.Errorf("History() = %q, want %q", got, want)
+	}
+
+	s.Reset()
+	if got := s.History(); len(got) != 0 {
+		t.Errorf("History() after Reset = %q, want empty", got)
+	}
+	if got, _ := s.Send(context.Background(), "fresh"); got != "Be brief.\n\nfresh" {
+		t.Errorf("Send after Reset sent %q, want no earlier turns", got)
+	}
+}
+
+func TestSessionSendFailureKeepsHistory(t *testing.T) {
+	dir := t.TempDir()
+	started := filepath.Join(dir, "started")
+	c := &Client{BinPath: stub.Write(t, dir, "claude", `msg=$(cat)
+case "$msg" in
+*slow) echo $$ > "`+started+`"; sleep 30 ;;
+*fail) exit 1 ;;
+*) echo ok ;;
+esac`)}
+	s := NewSession(c)
+	if _, err := s.Send(context.Background(), "first"); err != nil {
+		t.Fatal(err)
+	}
+	want := s.History()
+
+	if _, err := s.Send(context.Background(), "fail"); err == nil {
+		t.Error("Send(fail) error = nil")
+	}
+	if got := s.History(); !reflect.DeepEqual(got, want) {
+		t.Errorf("History() after a failed turn = %q, want %q", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Send(ctx, "slow")
+		done <- err
+	}()
+	stub.WaitPID(t, started)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Send() error = %v after cancellation, want context.Canceled", err)
+	}
+	if got := s.History(); !reflect.DeepEqual(got, want) {
+		t.Errorf("History() after a canceled turn = %q, want %q", got, want)
+	}
+}
+
+func TestSessionResetDiscardsTurnInFlight(t *testing.T) {
+	dir := t.TempDir()
+	started, release := filepath.Join(dir, "started"), filepath.Join(dir, "release")
+	c := &Client{BinPath: stub.Write(t, dir, "claude", `cat >/dev/null; echo $$ > "`+started+`"
+while [ ! -e "`+release+`" ]; do sleep 0.01; done; echo ok`)}
+	s := NewSession(c)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Send(context.Background(), "hi")
+		done <- err
+	}()
+	stub.WaitPID(t, started)
+	s.Reset()
+	if err := os.WriteFile(release, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if got := s.History(); len(got) != 0 {
+		t.Errorf("History() = %q, want the turn sent before Reset discarded", got)
+	}
+}
+
+func TestSessionSave(t *testing.T) {
+	dir := t.TempDir()
+	s := NewSession(&Client{BinPath: stub.Write(t, dir, "claude", `echo pong`)})
+	path := filepath.Join(dir, "chat.json")
+
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "[]\n" {
+		t.Errorf("empty transcript = %q, want []", data)
+	}
+
+	if _, err := s.Send(context.Background(), "ping"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Turn
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("transcript is not JSON: %v\n%s", err, data)
+	}
+	if want := []Turn{{User: "ping", Assistant: "pong\n"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("saved %q, want %q", got, want)
+	}
+
+	if err := s.Save(filepath.Join(dir, "new", "chat.json")); err != nil {
+		t.Errorf("Save() into a new directory error = %v", err)
+	}
+	if err := s.Save(filepath.Join(path, "chat.json")); err == nil {
+		t.Error("Save() under a regular file error = nil")
+	}
+}
+
+func TestSessionSendStream(t *testing.T) {
+	dir := t.TempDir()
+	s := NewSession(&Client{BinPath: stub.Write(t, dir, "claude", `cat >/dev/null; echo pong`)})
+	var out strings.Builder
+	reply, err := s.SendStream(context.Background(), "ping", &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "pong\n" || reply != "pong\n" {
+		t.Errorf("streamed %q and returned %q, want both pong", out.String(), reply)
+	}
+	if want := []Turn{{User: "ping", Assistant: "pong\n"}}; !reflect.DeepEqual(s.History(), want) {
+		t.Errorf("History() = %q, want %q", s.History(), want)
+	}
+}
@@ -0,0 +1,71 @@
+// Package color adds ANSI colors to terminal output while keeping piped
+// and redirected output plain.
+package color
+
+import (
+	"io"
+	"os"
+)
+
+// ANSI SGR sequences.
+const (
+	reset  = "\x1b[0m"
+	bold   = "\x1b[1m"
+	red    = "\x1b[31m"
+	green  = "\x1b[32m"
+	yellow = "\x1b[33m"
+	cyan   = "\x1b[36m"
+)
+
+// IsTerminal reports whether w is a character device such as a terminal.
+// Writers that are not *os.File are never terminals.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Enabled reports whether colors should be written to w. It returns false
+// when the NO_COLOR environment variable is set to a non-empty value (see
+// https://no-color.org) or when isTerminal reports that w is not a
+// terminal. isTerminal is normally IsTerminal; tests substitute their own.
+func Enabled(w io.Writer, isTerminal func(io.Writer) bool) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+// Colorizer wraps strings in ANSI color sequences when Enabled is true and
+// returns them unchanged otherwise. The zero value never colors.
+type Colorizer struct {
+	Enabled bool
+}
+
+func (c Colorizer) wrap(code, s string) string {
+	if !c.Enabled || s == "" {
+		return s
+	}
+	return code + s + reset
+}
+
+// Bold renders s in bold.
+func (c Colorizer) Bold(s string) string { return c.wrap(bold, s) }
+
+// Red renders s in red.
+func (c Colorizer) Red(s string) string { return c.wrap(red, s) }
+
+// Green renders s in green.
+func (c Colorizer) Green(s string) string { return c.wrap(green, s) }
+
+// Yellow renders s in yellow.
+func (c Colorizer) Yellow(s string) string { return c.wrap(yellow, s) }
+
+// Cyan renders s in cyan.
+func (c Colorizer) Cyan(s string) string { return c.wrap(cyan, s) }
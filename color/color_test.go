@@ -0,0 +1,74 @@
+package color
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	tty := func(io.Writer) bool { return true }
+	notTTY := func(io.Writer) bool { return false }
+
+	tests := []struct {
+		name       string
+		noColor    string
+		isTerminal func(io.Writer) bool
+		expected   bool
+	}{
+		{"terminal", "", tty, true},
+		{"not a terminal", "", notTTY, false},
+		{"NO_COLOR set", "1", tty, false},
+		{"NO_COLOR any value", "false", tty, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			if got := Enabled(io.Discard, tt.isTerminal); got != tt.expected {
+				t.Errorf("Enabled() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestIsTerminal(t *testing.T) {
+	if IsTerminal(&bytes.Buffer{}) {
+		t.Error("IsTerminal(*bytes.Buffer) = true, want false")
+	}
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if IsTerminal(f) {
+		t.Error("IsTerminal(regular file) = true, want false")
+	}
+}
+
+func TestColorizer(t *testing.T) {
+	tests := []struct {
+		name     string
+		c        Colorizer
+		fn       func(Colorizer, string) string
+		input    string
+		expected string
+	}{
+		{"green", Colorizer{Enabled: true}, Colorizer.Green, "ok", "\x1b[32mok\x1b[0m"},
+		{"red", Colorizer{Enabled: true}, Colorizer.Red, "missing", "\x1b[31mmissing\x1b[0m"},
+		{"yellow", Colorizer{Enabled: true}, Colorizer.Yellow, "warning", "\x1b[33mwarning\x1b[0m"},
+		{"cyan", Colorizer{Enabled: true}, Colorizer.Cyan, "hi", "\x1b[36mhi\x1b[0m"},
+		{"bold", Colorizer{Enabled: true}, Colorizer.Bold, "TOOL", "\x1b[1mTOOL\x1b[0m"},
+		{"empty string", Colorizer{Enabled: true}, Colorizer.Red, "", ""},
+		{"disabled", Colorizer{}, Colorizer.Green, "ok", "ok"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.c, tt.input); got != tt.expected {
+				t.Errorf("got %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/examples"
+	"github.com/ericbfriday/claude-go-containers/server"
+)
+
+// handler runs a subcommand once its flags have been parsed. args holds
+// the remaining positional arguments. A non-nil Output is rendered even
+// when err is non-nil, so commands can report partial results.
+type handler func(a *app, args []string) (Output, error)
+
+// command describes a subcommand in the registry.
+type command struct {
+	name    string
+	args    string // positional argument synopsis shown in usage
+	summary string
+	words   []string // fixed positional arguments offered by shell completion
+	// setFlags registers the command's flags on fs and returns the
+	// handler bound to them.
+	setFlags func(fs *flag.FlagSet) handler
+}
+
+// commands returns the registry of subcommands in the order they are listed
+// in usage output.
+func commands() []command {
+	return []command{
+		greetCommand(),
+		historyCommand(),
+		addCommand(),
+		calcCommand(),
+		fibCommand(),
+		statsCommand(),
+		doctorCommand(),
+		setupCommand(),
+		askCommand(),
+		chatCommand(),
+		batchCommand(),
+		benchCommand(),
+		watchCommand(),
+		serveCommand(),
+		replCommand(),
+		configCommand(),
+		completionCommand(),
+		versionCommand(),
+	}
+}
+
+// lookup finds a registered command by name.
+func lookup(name string) (command, bool) {
+	for _, cmd := range commands() {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func addCommand() command {
+	return command{
+		name:    "add",
+		args:    "<n>...",
+		summary: "Add any number of integers",
+		setFlags: func(fs *flag.FlagSet) handler {
+			return func(a *app, args []string) (Output, error) {
+				res := sumResult{Operands: make([]int, 0, len(args))}
+				for _, arg := range args {
+					n, err := strconv.Atoi(arg)
+					if err != nil {
+						return nil, usagef("invalid integer %q", arg)
+					}
+					if res.Result, err = examples.AddChecked(res.Result, n); err != nil {
+						return nil, err
+					}
+					res.Operands = append(res.Operands, n)
+				}
+				a.metrics.IncAddOperations()
+				return res, nil
+			}
+		},
+	}
+}
+
+func doctorCommand() command {
+	return command{
+		name:    "doctor",
+		args:    "[--format table|csv|json] [--min-go VERSION]",
+		summary: "Check that the bundled tools are installed",
+		setFlags: func(fs *flag.FlagSet) handler {
+			format := fs.String("format", "table", "output format: "+strings.Join(doctor.Formats, ", "))
+			minGo := fs.String("min-go", "", "fail unless go reports at least this `version`, such as 1.22 (default min_go from the configuration)")
+			return func(a *app, args []string) (Output, error) {
+				if !slices.Contains(doctor.Formats, *format) {
+					return nil, usagef("invalid format %q: want one of %s", *format, strings.Join(doctor.Formats, ", "))
+				}
+				if *minGo != "" {
+					if _, err := doctor.ParseGoVersion(*minGo); err != nil {
+						return nil, usagef("invalid --min-go: %v", err)
+					}
+				} else {
+					*minGo = a.config.MinGo
+				}
+				statuses, err := a.environment.Check()
+				if err != nil {
+					return nil, err
+				}
+				if *minGo != "" && a.dryRun == nil {
+					if err := doctor.ApplyMinGo(statuses, *minGo); err != nil {
+						return nil, err
+					}
+				}
+				report := toolReport{statuses: statuses, color: a.color, format: *format}
+				if missing := doctor.Missing(statuses); len(missing) > 0 {
+					return report, fmt.Errorf("missing required tools: %s", strings.Join(missing, ", "))
+				}
+				return report, nil
+			}
+		},
+	}
+}
+
+// checker returns a doctor.Checker that logs to the app's logger and
+// honors --dry-run.
+func (a *app) checker() *doctor.Checker {
+	return &doctor.Checker{Logger: a.logger, DryRun: a.dryRun}
+}
+
+// checkEnvironment probes the default tools with a.checker, within the
+// context of a tool-invoking operation. Commands call it through
+// a.environment rather than directly.
+func (a *app) checkEnvironment() ([]doctor.ToolStatus, error) {
+	ctx, stop := a.toolContext()
+	defer stop()
+	return a.checker().Check(ctx)
+}
+
+// interruptContext returns a context that is canceled on SIGINT or SIGTERM.
+// Commands that run tools use it so an interrupt stops the tools' process
+// groups, which no longer receive the terminal's signals themselves.
+func interruptContext() (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
+
+// toolContext returns the context for one tool-invoking operation, such
+// as an ask or a chat turn: it carries the invocation's request ID and is
+// canceled on SIGINT or SIGTERM, like interruptContext, and when --timeout
+// elapses.
+func (a *app) toolContext() (context.Context, context.CancelFunc) {
+	ctx, stop := interruptContext()
+	ctx, cancel := a.withTimeout(server.ContextWithRequestID(ctx, a.requestID))
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// withTimeout derives a context that is canceled once --timeout elapses,
+// with a *timeoutError as its cause. With no timeout it only adds a
+// cancel function.
+func (a *app) withTimeout(parent context.Context) (context.Context, context.CancelFunc) {
+	if a.timeout <= 0 {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeoutCause(parent, a.timeout, &timeoutError{after: a.timeout})
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/ericbfriday/claude-go-containers/internal/shellquote"
+)
+
+// completionShells lists the shells the completion command supports.
+var completionShells = []string{"bash", "zsh", "fish"}
+
+func completionCommand() command {
+	return command{
+		name:    "completion",
+		args:    "<bash|zsh|fish>",
+		summary: "Print a shell completion script",
+		words:   completionShells,
+		setFlags: func(fs *flag.FlagSet) handler {
+			return func(a *app, args []string) (Output, error) {
+				if len(args) != 1 {
+					return nil, usagef("expected exactly one shell: %s", strings.Join(completionShells, ", "))
+				}
+				shell := args[0]
+				if !slices.Contains(completionShells, shell) {
+					return nil, usagef("unsupported shell %q: want one of %s", shell, strings.Join(completionShells, ", "))
+				}
+				return completionScript{Shell: shell, Script: completionFor(shell, newCompletionSpec())}, nil
+			}
+		},
+	}
+}
+
+// completionSpec is the command-line surface that completion scripts
+// offer, collected from the command registry and global flags.
+type completionSpec struct {
+	flags    []flagSpec
+	commands []commandSpec
+}
+
+type commandSpec struct {
+	name, summary string
+	flags         []flagSpec
+	words         []string
+}
+
+type flagSpec struct {
+	name, usage string
+	takesValue  bool
+}
+
+func newCompletionSpec() completionSpec {
+	spec := completionSpec{flags: flagSpecs(newGlobalFlags(&globalOptions{}))}
+	for _, cmd := range commands() {
+		fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+		cmd.setFlags(fs)
+		spec.commands = append(spec.commands, commandSpec{
+			name:    cmd.name,
+			summary: cmd.summary,
+			flags:   flagSpecs(fs),
+			words:   cmd.words,
+		})
+	}
+	return spec
+}
+
+func flagSpecs(fs *flag.FlagSet) []flagSpec {
+	var specs []flagSpec
+	fs.VisitAll(func(f *flag.Flag) {
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		specs = append(specs, flagSpec{name: f.Name, usage: f.Usage, takesValue: !ok || !b.IsBoolFlag()})
+	})
+	return specs
+}
+
+// valueFlags returns the "--name" spellings of the global flags that
+// consume the following word, so scripts can skip over their values when
+// looking for the command name.
+func (s completionSpec) valueFlags() []string {
+	var names []string
+	for _, f := range s.flags {
+		if f.takesValue {
+			names = append(names, "--"+f.name)
+		}
+	}
+	return names
+}
+
+func flagWords(flags []flagSpec) []string {
+	words := make([]string, len(flags))
+	for i, f := range flags {
+		words[i] = "--" + f.name
+	}
+	return words
+}
+
+func completionFor(shell string, spec completionSpec) string {
+	switch shell {
+	case "bash":
+		return bashCompletion(spec)
+	case "zsh":
+		return zshCompletion(spec)
+	default:
+		return fishCompletion(spec)
+	}
+}
+
+func bashCompletion(spec completionSpec) string {
+	var b strings.Builder
+	fn := "_" + progName
+	var names []string
+	for _, c := range spec.commands {
+		names = append(names, c.name)
+	}
+
+	fmt.Fprintf(&b, "# bash completion for %s\n", progName)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local cur=\"${COMP_WORDS[COMP_CWORD]}\" cmd=\"\" i\n")
+	b.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	b.WriteString("        case \"${COMP_WORDS[i]}\" in\n")
+	if vf := spec.valueFlags(); len(vf) > 0 {
+		fmt.Fprintf(&b, "            %s) ((i++)) ;;\n", strings.Join(vf, "|"))
+	}
+	b.WriteString("            -*) ;;\n")
+	b.WriteString("            *) cmd=\"${COMP_WORDS[i]}\"; break ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    case \"$cmd\" in\n")
+	fmt.Fprintf(&b, "        \"\") COMPREPLY=($(compgen -W %s -- \"$cur\")) ;;\n",
+		shellquote.Quote(strings.Join(append(names, flagWords(spec.flags)...), " ")))
+	for _, c := range spec.commands {
+		words := append(flagWords(c.flags), c.words...)
+		if len(words) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "        %s) COMPREPLY=($(compgen -W %s -- \"$cur\")) ;;\n", c.name, shellquote.Quote(strings.Join(words, " ")))
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n")
+	fmt.Fprintf(&b, "complete -F %s %s\n", fn, progName)
+	return b.String()
+}
+
+func zshCompletion(spec completionSpec) string {
+	var b strings.Builder
+	fn := "_" + progName
+
+	fmt.Fprintf(&b, "#compdef %s\n\n", progName)
+	fmt.Fprintf(&b, "%s() {\n", fn)
+	b.WriteString("    local -a commands\n")
+	b.WriteString("    commands=(\n")
+	for _, c := range spec.commands {
+		fmt.Fprintf(&b, "        %s\n", shellquote.Quote(c.name+":"+c.summary))
+	}
+	b.WriteString("    )\n")
+	b.WriteString("    local cmd=\"\" i\n")
+	b.WriteString("    for ((i = 2; i < CURRENT; i++)); do\n")
+	b.WriteString("        case \"${words[i]}\" in\n")
+	if vf := spec.valueFlags(); len(vf) > 0 {
+		fmt.Fprintf(&b, "            %s) ((i++)) ;;\n", strings.Join(vf, "|"))
+	}
+	b.WriteString("            -*) ;;\n")
+	b.WriteString("            *) cmd=\"${words[i]}\"; break ;;\n")
+	b.WriteString("        esac\n")
+	b.WriteString("    done\n")
+	b.WriteString("    case \"$cmd\" in\n")
+	fmt.Fprintf(&b, "        \"\")\n            _describe 'command' commands\n            compadd -- %s\n            ;;\n",
+		strings.Join(flagWords(spec.flags), " "))
+	for _, c := range spec.commands {
+		words := append(flagWords(c.flags), c.words...)
+		if len(words) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "        %s) compadd -- %s ;;\n", c.name, shellquote.Quote(strings.Join(words, " ")))
+	}
+	b.WriteString("    esac\n")
+	b.WriteString("}\n\n")
+	fmt.Fprintf(&b, "compdef %s %s\n", fn, progName)
+	return b.String()
+}
+
+func fishCompletion(spec completionSpec) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# fish completion for %s\n", progName)
+	fmt.Fprintf(&b, "complete -c %s -f\n", progName)
+	for _, f := range spec.flags {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -l %s%s -d %s\n",
+			progName, f.name, fishRequires(f), shellquote.Quote(f.usage))
+	}
+	for _, c := range spec.commands {
+		fmt.Fprintf(&b, "complete -c %s -n __fish_use_subcommand -a %s -d %s\n",
+			progName, c.name, shellquote.Quote(c.summary))
+	}
+	for _, c := range spec.commands {
+		cond := shellquote.Quote("__fish_seen_subcommand_from " + c.name)
+		for _, f := range c.flags {
+			fmt.Fprintf(&b, "complete -c %s -n %s -l %s%s -d %s\n",
+				progName, cond, f.name, fishRequires(f), shellquote.Quote(f.usage))
+		}
+		if len(c.words) > 0 {
+			fmt.Fprintf(&b, "complete -c %s -n %s -a %s\n", progName, cond, shellquote.Quote(strings.Join(c.words, " ")))
+		}
+	}
+	return b.String()
+}
+
+func fishRequires(f flagSpec) string {
+	if f.takesValue {
+		return " -r"
+	}
+	return ""
+}
+
+// completionScript is the result of the completion command.
+type completionScript struct {
+	Shell  string `json:"shell"`
+	Script string `json:"script"`
+}
+
+func (c completionScript) Text(w io.Writer) error {
+	_, err := io.WriteString(w, c.Script)
+	return err
+}
+
+func (c completionScript) JSON(w io.Writer) error { return writeJSON(w, c) }
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestCompletionCoversRegistry(t *testing.T) {
+	for _, shell := range completionShells {
+		t.Run(shell, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run([]string{"completion", shell}, &out, &errOut); code != exitOK {
+				t.Fatalf("completion %s = %d (stderr: %s)", shell, code, errOut.String())
+			}
+			script := out.String()
+
+			var want []string
+			newGlobalFlags(&globalOptions{}).VisitAll(func(f *flag.Flag) { want = append(want, f.Name) })
+			for _, cmd := range commands() {
+				want = append(want, cmd.name)
+				fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+				cmd.setFlags(fs)
+				fs.VisitAll(func(f *flag.Flag) { want = append(want, f.Name) })
+			}
+			for _, w := range want {
+				if !strings.Contains(script, w) {
+					t.Errorf("%s script does not mention %q", shell, w)
+				}
+			}
+		})
+	}
+}
+
+func TestCompletionScriptsParse(t *testing.T) {
+	for _, shell := range completionShells {
+		t.Run(shell, func(t *testing.T) {
+			path, err := exec.LookPath(shell)
+			if err != nil {
+				t.Skipf("%s not installed", shell)
+			}
+			noExec := "-n"
+			if shell == "fish" {
+				noExec = "--no-execute"
+			}
+			cmd := exec.Command(path, noExec)
+			cmd.Stdin = strings.NewReader(completionFor(shell, newCompletionSpec()))
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("%s rejected the script: %v\n%s", shell, err, out)
+			}
+		})
+	}
+}
+
+func TestCompletionBashSkipsFlagValues(t *testing.T) {
+	script := completionFor("bash", newCompletionSpec())
+	if !strings.Contains(script, "--config|--log-format|--log-level|--output|--timeout) ((i++)) ;;") {
+		t.Errorf("bash script does not skip values of global flags:\n%s", script)
+	}
+}
+
+func TestCompletionBashCompletes(t *testing.T) {
+	path, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not installed")
+	}
+	script := completionFor("bash", newCompletionSpec()) +
+		"COMP_WORDS=(myapp gr); COMP_CWORD=1; _myapp; echo \"${COMPREPLY[*]}\"\n"
+	cmd := exec.Command(path)
+	cmd.Stdin = strings.NewReader(script)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("bash: %v\n%s", err, out)
+	}
+	if got := strings.TrimSpace(string(out)); got != "greet" {
+		t.Errorf("completing %q offered %q, want %q", "gr", got, "greet")
+	}
+}
+
+func TestCompletionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown shell", []string{"completion", "powershell"}, `unsupported shell "powershell": want one of bash, zsh, fish`},
+		{"missing shell", []string{"completion"}, "expected exactly one shell: bash, zsh, fish"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != exitUsage {
+				t.Errorf("run(%q) = %d, want %d", tt.args, code, exitUsage)
+			}
+			if !strings.Contains(errOut.String(), tt.want) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"io"
+
+	"github.com/ericbfriday/claude-go-containers/config"
+	"github.com/ericbfriday/claude-go-containers/internal/table"
+)
+
+func configCommand() command {
+	return command{
+		name:    "config",
+		args:    "--show",
+		summary: "Show the resolved configuration and where each setting came from",
+		setFlags: func(fs *flag.FlagSet) handler {
+			show := fs.Bool("show", false, "print every setting with its value and source")
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 {
+					return nil, usagef("unexpected arguments: %v", args)
+				}
+				if !*show {
+					return nil, usagef("nothing to do: use --show to print the configuration")
+				}
+				return configReport{File: a.config.Path, Settings: a.config.Settings()}, nil
+			}
+		},
+	}
+}
+
+// configReport is the result of config --show. File is the configuration
+// file that was read, if any.
+type configReport struct {
+	File     string           `json:"file"`
+	Settings []config.Setting `json:"settings"`
+}
+
+// Text writes a KEY, VALUE, SOURCE table. The source of a value from the
+// environment or a flag names the variable or flag.
+func (r configReport) Text(w io.Writer) error {
+	rows := [][]string{{"KEY", "VALUE", "SOURCE"}}
+	for _, s := range r.Settings {
+		rows = append(rows, []string{s.Key, s.Value, s.Origin.String()})
+	}
+	return table.Write(w, rows, nil)
+}
+
+func (r configReport) JSON(w io.Writer) error { return writeJSON(w, r) }
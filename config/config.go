@@ -0,0 +1,349 @@
+// Package config resolves the CLI's settings from command-line flags,
+// MYAPP_ environment variables, an optional YAML configuration file and
+// built-in defaults, in that order of precedence.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/examples"
+)
+
+// EnvPrefix starts the name of every environment variable Load reads.
+const EnvPrefix = "MYAPP_"
+
+// DefaultTimeout is the default for Config.Timeout.
+const DefaultTimeout = 120 * time.Second
+
+// Config is the resolved configuration.
+type Config struct {
+	// GreetingTemplate replaces the built-in greeting format. It is a
+	// text/template executed with examples.TemplateData, for example
+	// "Hey {{.Name}}, welcome!".
+	GreetingTemplate string
+	// LogLevel is the minimum level of log records written to stderr.
+	LogLevel slog.Level
+	// LogFormat is "text" or "json".
+	LogFormat string
+	// Timeout bounds each tool-invoking operation; zero means no limit.
+	Timeout time.Duration
+	// MinGo, if set, is the oldest Go version doctor accepts.
+	MinGo string
+
+	// Path is the configuration file that was read, or empty if there
+	// was none.
+	Path string
+
+	origins map[string]Origin
+}
+
+// Source identifies where a setting's value came from.
+type Source string
+
+// The sources of a setting, from lowest to highest precedence.
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Origin is where a setting's value came from: for SourceFile the file's
+// path, for SourceEnv the variable and for SourceFlag the flag, such as
+// "--timeout". Name is empty for SourceDefault.
+type Origin struct {
+	Source Source `json:"source"`
+	Name   string `json:"name,omitempty"`
+}
+
+func (o Origin) String() string {
+	if o.Name == "" {
+		return string(o.Source)
+	}
+	return string(o.Source) + " " + o.Name
+}
+
+// setting is a configuration key. Its environment variable is the key in
+// upper case with EnvPrefix, and its flag, if it has one, is the key with
+// dashes for underscores.
+type setting struct {
+	key     string
+	hasFlag bool
+	def     string
+	set     func(c *Config, v string) error
+	get     func(c *Config) string
+}
+
+var settings = []setting{
+	{
+		key: "greeting_template",
+		set: func(c *Config, v string) error {
+			if v != "" {
+				if _, err := examples.GreetTemplate("", v); err != nil {
+					return err
+				}
+			}
+			c.GreetingTemplate = v
+			return nil
+		},
+		get: func(c *Config) string { return c.GreetingTemplate },
+	},
+	{
+		key: "log_level", hasFlag: true, def: "warn",
+		set: func(c *Config, v string) error { return c.LogLevel.UnmarshalText([]byte(v)) },
+		get: func(c *Config) string { return strings.ToLower(c.LogLevel.String()) },
+	},
+	{
+		key: "log_format", hasFlag: true, def: "text",
+		set: func(c *Config, v string) error {
+			if v != "text" && v != "json" {
+				return fmt.Errorf("invalid log format %q: want text or json", v)
+			}
+			c.LogFormat = v
+			return nil
+		},
+		get: func(c *Config) string { return c.LogFormat },
+	},
+	{
+		key: "timeout", hasFlag: true, def: DefaultTimeout.String(),
+		set: func(c *Config, v string) error {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return err
+			}
+			if d < 0 {
+				return errors.New("must not be negative")
+			}
+			c.Timeout = d
+			return nil
+		},
+		get: func(c *Config) string { return c.Timeout.String() },
+	},
+	{
+		key: "min_go",
+		set: func(c *Config, v string) error {
+			if v != "" {
+				if _, err := doctor.ParseGoVersion(v); err != nil {
+					return err
+				}
+			}
+			c.MinGo = v
+			return nil
+		},
+		get: func(c *Config) string { return c.MinGo },
+	},
+}
+
+// Default returns the configuration with every setting at its default.
+func Default() *Config {
+	c := &Config{}
+	for _, s := range settings {
+		if err := s.set(c, s.def); err != nil {
+			panic("config: invalid default for " + s.key + ": " + err.Error())
+		}
+	}
+	return c
+}
+
+// Keys returns the configuration keys in the order Settings lists them.
+func Keys() []string {
+	keys := make([]string, len(settings))
+	for i, s := range settings {
+		keys[i] = s.key
+	}
+	return keys
+}
+
+// EnvVar returns the environment variable that sets key.
+func EnvVar(key string) string { return EnvPrefix + strings.ToUpper(key) }
+
+// FlagName returns the global flag that sets key, without dashes, or ""
+// if it can only be set by environment variable or file.
+func FlagName(key string) string {
+	for _, s := range settings {
+		if s.key == key && s.hasFlag {
+			return strings.ReplaceAll(key, "_", "-")
+		}
+	}
+	return ""
+}
+
+// Setting is a resolved setting as reported by Config.Settings.
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Origin Origin `json:"origin"`
+}
+
+// Settings returns every setting with its value and origin, in a fixed
+// order.
+func (c *Config) Settings() []Setting {
+	out := make([]Setting, len(settings))
+	for i, s := range settings {
+		out[i] = Setting{Key: s.key, Value: s.get(c), Origin: c.Origin(s.key)}
+	}
+	return out
+}
+
+// Origin reports where the value of key came from.
+func (c *Config) Origin(key string) Origin {
+	if o, ok := c.origins[key]; ok {
+		return o
+	}
+	return Origin{Source: SourceDefault}
+}
+
+// Options are the inputs to Load.
+type Options struct {
+	// Path is the configuration file to read. If empty, the file named by
+	// $MYAPP_CONFIG is read, and failing that the one at DefaultPath if it
+	// exists. A file named explicitly must exist.
+	Path string
+	// Flags holds the settings given on the command line, by key, as the
+	// flags' string values. Only flags that were actually set belong here,
+	// so that a flag's default does not hide the environment or file.
+	Flags map[string]string
+	// FlagNames names, by key, the flag that set a value in Flags when it
+	// is not the key's own, such as "--quiet" for log_level. It only
+	// affects the reported Origin.
+	FlagNames map[string]string
+	// Getenv looks up environment variables. If nil, os.Getenv is used.
+	Getenv func(key string) string
+}
+
+// SettingError reports a setting whose value is invalid.
+type SettingError struct {
+	Key    string
+	Origin Origin
+	Err    error
+}
+
+func (e *SettingError) Error() string {
+	return fmt.Sprintf("config: %s from %s: %v", e.Key, e.Origin, e.Err)
+}
+
+func (e *SettingError) Unwrap() error { return e.Err }
+
+// DefaultPath returns the configuration file used when none is given:
+// myapp/config.yaml under the user configuration directory, which is
+// $XDG_CONFIG_HOME or ~/.config on Linux.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "myapp", "config.yaml"), nil
+}
+
+// Load resolves every setting from, in decreasing order of precedence,
+// opts.Flags, the environment, the configuration file and the defaults.
+// An empty environment variable counts as unset. Keys in the file that
+// Load does not know are ignored.
+//
+// Every value is validated, the greeting template by rendering it once, so
+// mistakes are reported when the configuration is loaded rather than on
+// first use; the error is a *SettingError naming the offending source.
+func Load(opts Options) (*Config, error) {
+	getenv := opts.Getenv
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+	file, path, err := readFile(opts.Path, getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Config{Path: path, origins: make(map[string]Origin)}
+	for _, s := range settings {
+		value, origin := s.def, Origin{Source: SourceDefault}
+		if v, ok := file[s.key]; ok {
+			value, origin = v, Origin{Source: SourceFile, Name: path}
+		}
+		if v := getenv(EnvVar(s.key)); v != "" {
+			value, origin = v, Origin{Source: SourceEnv, Name: EnvVar(s.key)}
+		}
+		if v, ok := opts.Flags[s.key]; ok && s.hasFlag {
+			name := "--" + FlagName(s.key)
+			if n, ok := opts.FlagNames[s.key]; ok {
+				name = n
+			}
+			value, origin = v, Origin{Source: SourceFlag, Name: name}
+		}
+		if err := s.set(c, value); err != nil {
+			return nil, &SettingError{Key: s.key, Origin: origin, Err: err}
+		}
+		if origin.Source != SourceDefault {
+			c.origins[s.key] = origin
+		}
+	}
+	return c, nil
+}
+
+// readFile reads the configuration file chosen as described for
+// Options.Path and returns its known settings as strings, along with its
+// path. Both are empty if no file was read.
+func readFile(path string, getenv func(string) string) (map[string]string, string, error) {
+	explicit := true
+	if path == "" {
+		path = getenv(EnvPrefix + "CONFIG")
+	}
+	if path == "" {
+		explicit = false
+		p, err := DefaultPath()
+		if err != nil {
+			return nil, "", nil
+		}
+		path = p
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return nil, "", nil
+		}
+		return nil, "", fmt.Errorf("config: %w", err)
+	}
+
+	// Values are taken as written rather than decoded, so that min_go:
+	// 1.20 means Go 1.20 and not the float 1.2.
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, "", fmt.Errorf("config: %s: %w", path, err)
+	}
+	raw := make(map[string]*yaml.Node)
+	if len(doc.Content) > 0 {
+		root := doc.Content[0]
+		if root.Kind != yaml.MappingNode {
+			return nil, "", fmt.Errorf("config: %s: want a mapping of settings", path)
+		}
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			raw[root.Content[i].Value] = root.Content[i+1]
+		}
+	}
+	file := make(map[string]string)
+	for _, s := range settings {
+		v, ok := raw[s.key]
+		for ok && v.Kind == yaml.AliasNode {
+			v = v.Alias
+		}
+		switch {
+		case !ok, v.Kind == yaml.ScalarNode && v.Tag == "!!null":
+			// Absent, or present with no value: use the next source.
+		case v.Kind != yaml.ScalarNode:
+			return nil, "", fmt.Errorf("config: %s: %s must be a single value", path, s.key)
+		default:
+			file[s.key] = v.Value
+		}
+	}
+	return file, path, nil
+}
@@ -0,0 +1,214 @@
+package config
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// env returns a Getenv function serving vars.
+func env(vars map[string]string) func(string) string {
+	return func(key string) string { return vars[key] }
+}
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		check    func(*Config) bool
+		wantErr  string
+	}{
+		{"template", "greeting_template: \"Hey {{.Name}}, welcome!\"\n", func(c *Config) bool { return c.GreetingTemplate == "Hey {{.Name}}, welcome!" }, ""},
+		{"empty file", "", func(c *Config) bool { return reflect.DeepEqual(c.Settings(), Default().Settings()) }, ""},
+		{"unknown keys ignored", "other: [1]\n", func(c *Config) bool { return c.GreetingTemplate == "" }, ""},
+		{"all settings", "log_level: debug\nlog_format: json\ntimeout: 30s\nmin_go: \"1.22\"\n", func(c *Config) bool {
+			return c.LogLevel == slog.LevelDebug && c.LogFormat == "json" && c.Timeout == 30*time.Second && c.MinGo == "1.22"
+		}, ""},
+		{"version kept as written", "min_go: 1.20\n", func(c *Config) bool { return c.MinGo == "1.20" }, ""},
+		{"alias", "base: &t 45s\ntimeout: *t\n", func(c *Config) bool { return c.Timeout == 45*time.Second }, ""},
+		{"explicit null uses default", "timeout: ~\n", func(c *Config) bool { return c.Timeout == DefaultTimeout }, ""},
+		{"not a mapping", "- timeout\n", nil, "want a mapping"},
+		{"null value uses default", "timeout:\n", func(c *Config) bool { return c.Timeout == DefaultTimeout }, ""},
+		{"invalid yaml", "greeting_template: [\n", nil, "yaml"},
+		{"invalid template", "greeting_template: \"Hey {{.Name\"\n", nil, "greeting_template"},
+		{"unknown template field", "greeting_template: \"Hey {{.Naem}}\"\n", nil, `unknown template field "Naem"`},
+		{"duration without unit", "timeout: 30\n", nil, "timeout from file"},
+		{"negative timeout", "timeout: -1s\n", nil, "must not be negative"},
+		{"invalid min_go", "min_go: soon\n", nil, "min_go from file"},
+		{"invalid log format", "log_format: xml\n", nil, "invalid log format"},
+		{"structured value", "timeout: {seconds: 3}\n", nil, "timeout must be a single value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(Options{Path: writeConfig(t, tt.contents), Getenv: env(nil)})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !tt.check(got) {
+				t.Errorf("Load() = %+v", *got)
+			}
+		})
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, "timeout: 10s\nlog_level: info\nlog_format: json\ngreeting_template: \"file {{.Name}}\"\n")
+	vars := map[string]string{
+		"MYAPP_TIMEOUT":           "20s",
+		"MYAPP_LOG_LEVEL":         "error",
+		"MYAPP_GREETING_TEMPLATE": "", // empty counts as unset
+	}
+	flags := map[string]string{"timeout": "30s"}
+
+	c, err := Load(Options{Path: path, Flags: flags, Getenv: env(vars)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		key        string
+		value      string
+		wantOrigin Origin
+	}{
+		{"timeout", "30s", Origin{SourceFlag, "--timeout"}},               // flag beats env and file
+		{"log_level", "error", Origin{SourceEnv, "MYAPP_LOG_LEVEL"}},      // env beats file
+		{"log_format", "json", Origin{SourceFile, path}},                  // file beats default
+		{"greeting_template", "file {{.Name}}", Origin{SourceFile, path}}, // empty env ignored
+		{"min_go", "", Origin{Source: SourceDefault}},                     // nothing set
+	}
+	settings := make(map[string]Setting)
+	for _, s := range c.Settings() {
+		settings[s.Key] = s
+	}
+	for _, tt := range tests {
+		if got := settings[tt.key]; got.Value != tt.value || got.Origin != tt.wantOrigin {
+			t.Errorf("%s = %q from %v, want %q from %v", tt.key, got.Value, got.Origin, tt.value, tt.wantOrigin)
+		}
+	}
+	if c.Timeout != 30*time.Second || c.LogLevel != slog.LevelError || c.Path != path {
+		t.Errorf("Load() = %+v", *c)
+	}
+
+	// Without the flag the environment wins, and without that the file.
+	c, _ = Load(Options{Path: path, Getenv: env(vars)})
+	if c.Timeout != 20*time.Second {
+		t.Errorf("Timeout without the flag = %v, want the environment's 20s", c.Timeout)
+	}
+	c, _ = Load(Options{Path: path, Getenv: env(nil)})
+	if c.Timeout != 10*time.Second {
+		t.Errorf("Timeout without flag or environment = %v, want the file's 10s", c.Timeout)
+	}
+}
+
+func TestLoadFlagsOnlyForFlagSettings(t *testing.T) {
+	c, err := Load(Options{Path: writeConfig(t, ""), Flags: map[string]string{"min_go": "1.30"}, Getenv: env(nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.MinGo != "" {
+		t.Errorf("MinGo = %q, want the flag ignored since min_go has no global flag", c.MinGo)
+	}
+	if FlagName("min_go") != "" || FlagName("log_level") != "log-level" {
+		t.Errorf("FlagName() = %q, %q", FlagName("min_go"), FlagName("log_level"))
+	}
+}
+
+func TestLoadFlagNames(t *testing.T) {
+	c, err := Load(Options{
+		Path:      writeConfig(t, "log_level: info\n"),
+		Flags:     map[string]string{"log_level": "error"},
+		FlagNames: map[string]string{"log_level": "--quiet"},
+		Getenv:    env(nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.LogLevel != slog.LevelError || c.Origin("log_level") != (Origin{SourceFlag, "--quiet"}) {
+		t.Errorf("log_level = %v from %v, want ERROR from flag --quiet", c.LogLevel, c.Origin("log_level"))
+	}
+}
+
+func TestLoadSettingError(t *testing.T) {
+	_, err := Load(Options{Path: writeConfig(t, ""), Getenv: env(map[string]string{"MYAPP_TIMEOUT": "soon"})})
+	var serr *SettingError
+	if !errors.As(err, &serr) || serr.Key != "timeout" || serr.Origin != (Origin{SourceEnv, "MYAPP_TIMEOUT"}) {
+		t.Fatalf("Load() error = %v, want a SettingError for MYAPP_TIMEOUT", err)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	got, err := Load(Options{Getenv: env(nil)})
+	if err != nil {
+		t.Fatalf("Load() error = %v, want missing default file to be ignored", err)
+	}
+	if got.Path != "" || !reflect.DeepEqual(got.Settings(), Default().Settings()) {
+		t.Errorf("Load() = %+v, want the defaults", *got)
+	}
+
+	missing := filepath.Join(t.TempDir(), "nope.yaml")
+	if _, err := Load(Options{Path: missing, Getenv: env(nil)}); err == nil {
+		t.Error("Load(explicit missing path) error = nil, want error")
+	}
+	if _, err := Load(Options{Getenv: env(map[string]string{"MYAPP_CONFIG": missing})}); err == nil {
+		t.Error("Load(missing $MYAPP_CONFIG) error = nil, want error")
+	}
+}
+
+func TestLoadDefaultPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	if err := os.MkdirAll(filepath.Join(dir, "myapp"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "myapp", "config.yaml"), []byte("greeting_template: hi\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(Options{Getenv: env(nil)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GreetingTemplate != "hi" {
+		t.Errorf("GreetingTemplate = %q, want %q", got.GreetingTemplate, "hi")
+	}
+
+	other := writeConfig(t, "greeting_template: from env\n")
+	if got, _ := Load(Options{Getenv: env(map[string]string{"MYAPP_CONFIG": other})}); got.GreetingTemplate != "from env" || got.Path != other {
+		t.Errorf("Load() with $MYAPP_CONFIG = %+v, want that file read", got)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	c := Default()
+	if c.LogLevel != slog.LevelWarn || c.LogFormat != "text" || c.Timeout != DefaultTimeout || c.GreetingTemplate != "" || c.MinGo != "" {
+		t.Errorf("Default() = %+v", *c)
+	}
+	for _, s := range c.Settings() {
+		if s.Origin.Source != SourceDefault {
+			t.Errorf("%s comes from %v, want default", s.Key, s.Origin)
+		}
+	}
+}
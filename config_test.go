@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigShow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("timeout: 10s\nlog_level: info\nmin_go: \"1.21\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	vars := map[string]string{"MYAPP_TIMEOUT": "20s", "MYAPP_LOG_LEVEL": "error"}
+	show := func(args ...string) string {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		a := newApp(strings.NewReader(""), &out, &errOut)
+		a.getenv = func(key string) string { return vars[key] }
+		if code := a.run(append([]string{"--config", path}, args...)); code != exitOK {
+			t.Fatalf("run(%q) = %d, want %d; stderr: %s", args, code, exitOK, errOut.String())
+		}
+		return out.String()
+	}
+
+	text := show("--timeout", "30s", "config", "--show")
+	for _, want := range []string{
+		"KEY ", "timeout            30s    flag --timeout\n",
+		"log_level          error  env MYAPP_LOG_LEVEL\n",
+		"min_go             1.21   file " + path + "\n",
+		"log_format         text   default\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("config --show =\n%s\nwant it to contain %q", text, want)
+		}
+	}
+
+	var report configReport
+	if err := json.Unmarshal([]byte(show("--json", "config", "--show")), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.File != path {
+		t.Errorf("file = %q, want %q", report.File, path)
+	}
+	for _, s := range report.Settings {
+		if s.Key == "timeout" && (s.Value != "20s" || s.Origin.Name != "MYAPP_TIMEOUT") {
+			t.Errorf("timeout = %+v, want 20s from MYAPP_TIMEOUT", s)
+		}
+	}
+}
+
+func TestConfigInvalidSetting(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want int
+	}{
+		{"bad flag", nil, []string{"--log-level", "loud", "config", "--show"}, exitUsage},
+		{"bad env", map[string]string{"MYAPP_LOG_FORMAT": "xml"}, []string{"config", "--show"}, exitError},
+		{"no --show", nil, []string{"config"}, exitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			a := newApp(strings.NewReader(""), &out, &errOut)
+			a.getenv = func(key string) string { return tt.env[key] }
+			if code := a.run(tt.args); code != tt.want {
+				t.Errorf("run(%q) = %d, want %d; stderr: %s", tt.args, code, tt.want, errOut.String())
+			}
+		})
+	}
+}
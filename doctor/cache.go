@@ -0,0 +1,125 @@
+package doctor
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is a TTL for an EnvironmentCache in a long-running
+// process: long enough that frequent readiness probes do not each spawn a
+// subprocess per tool, short enough that a newly installed tool is soon
+// noticed.
+const DefaultCacheTTL = 30 * time.Second
+
+// EnvironmentCache memoizes an environment check, such as
+// CheckEnvironment, so that the commands and handlers of one process share
+// a single set of version probes. Its Check method has the signature of
+// CheckEnvironment and can be used in its place.
+//
+// An EnvironmentCache is safe for concurrent use. Callers that arrive
+// while a check is running wait for it and share its result rather than
+// starting their own, whatever the TTL.
+type EnvironmentCache struct {
+	check func() ([]ToolStatus, error)
+	now   func() time.Time
+
+	mu        sync.Mutex // guards the fields below
+	ttl       time.Duration
+	statuses  []ToolStatus
+	checkedAt time.Time // zero if there is no cached result
+	flight    *envCall  // the check in progress, if any
+	gen       int       // incremented by Invalidate
+}
+
+// envCall is a check in progress. Its results are set before done is
+// closed.
+type envCall struct {
+	done     chan struct{}
+	gen      int
+	statuses []ToolStatus
+	err      error
+}
+
+// CachedEnvironment returns a function like CheckEnvironment that reuses
+// its result for ttl. Use NewEnvironmentCache for a cache that can be
+// invalidated.
+func CachedEnvironment(ttl time.Duration) func() ([]ToolStatus, error) {
+	return NewEnvironmentCache(CheckEnvironment, ttl).Check
+}
+
+// NewEnvironmentCache returns an EnvironmentCache that reuses the result of
+// check for ttl. A TTL of zero or less caches nothing, though concurrent
+// callers still share a check.
+func NewEnvironmentCache(check func() ([]ToolStatus, error), ttl time.Duration) *EnvironmentCache {
+	return &EnvironmentCache{check: check, ttl: ttl, now: time.Now}
+}
+
+// SetTTL changes how long results are reused, including the one already
+// cached.
+func (c *EnvironmentCache) SetTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// Check returns the cached statuses, checking the environment if there are
+// none or they are older than the TTL, or waiting for the check already
+// running. A failed check is not cached, so the next call tries again.
+// The statuses are copies, which the caller may modify.
+func (c *EnvironmentCache) Check() ([]ToolStatus, error) {
+	c.mu.Lock()
+	if !c.checkedAt.IsZero() && c.now().Sub(c.checkedAt) < c.ttl {
+		statuses := cloneStatuses(c.statuses)
+		c.mu.Unlock()
+		return statuses, nil
+	}
+	if f := c.flight; f != nil {
+		c.mu.Unlock()
+		<-f.done
+		return cloneStatuses(f.statuses), f.err
+	}
+	f := &envCall{done: make(chan struct{}), gen: c.gen}
+	c.flight = f
+	c.mu.Unlock()
+
+	f.statuses, f.err = c.check()
+
+	c.mu.Lock()
+	// A check started before Invalidate is neither cached nor the flight
+	// any longer.
+	if c.gen == f.gen {
+		c.flight = nil
+		if f.err == nil {
+			c.statuses, c.checkedAt = f.statuses, c.now()
+		}
+	}
+	c.mu.Unlock()
+	close(f.done)
+	return cloneStatuses(f.statuses), f.err
+}
+
+// Invalidate discards the cached result, so the next Check probes the
+// tools again. A check already running is not interrupted, and its callers
+// still receive its result, but it is not cached.
+func (c *EnvironmentCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.statuses, c.checkedAt, c.flight = nil, time.Time{}, nil
+	c.gen++
+}
+
+// cloneStatuses returns a deep copy of statuses.
+func cloneStatuses(statuses []ToolStatus) []ToolStatus {
+	if statuses == nil {
+		return nil
+	}
+	out := make([]ToolStatus, len(statuses))
+	for i, s := range statuses {
+		if s.Constraint != nil {
+			c := *s.Constraint
+			s.Constraint = &c
+		}
+		out[i] = s
+	}
+	return out
+}
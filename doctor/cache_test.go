@@ -0,0 +1,243 @@
+package doctor
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+// stubDefaultTools installs stubs for DefaultTools that record each run
+// as a line in the returned file.
+func stubDefaultTools(t *testing.T) (runs string) {
+	t.Helper()
+	dir := t.TempDir()
+	runs = filepath.Join(dir, "runs")
+	for _, tool := range DefaultTools {
+		stub.Write(t, dir, tool.Name, `echo `+tool.Name+` >> "`+runs+`"; sleep 0.1; echo "`+tool.Name+` 1.0"`)
+	}
+	stub.PrependPath(t, dir)
+	return runs
+}
+
+// countRuns returns how many times the stubs have run.
+func countRuns(t *testing.T, runs string) int {
+	t.Helper()
+	data, err := os.ReadFile(runs)
+	if os.IsNotExist(err) {
+		return 0
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "\n")
+}
+
+func TestCachedEnvironmentConcurrentFirstCallers(t *testing.T) {
+	runs := stubDefaultTools(t)
+	check := CachedEnvironment(time.Minute)
+
+	const callers = 10
+	start := make(chan struct{})
+	results := make([][]ToolStatus, callers)
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			statuses, err := check()
+			if err != nil {
+				t.Errorf("check() error = %v", err)
+			}
+			results[i] = statuses
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	if got, want := countRuns(t, runs), len(DefaultTools); got != want {
+		t.Errorf("stubs ran %d times for %d concurrent callers, want %d", got, callers, want)
+	}
+	for i, statuses := range results {
+		if len(statuses) != len(DefaultTools) || !statuses[0].Available {
+			t.Errorf("caller %d got %+v, want every default tool available", i, statuses)
+		}
+	}
+}
+
+func TestCachedEnvironmentExpiry(t *testing.T) {
+	runs := stubDefaultTools(t)
+	clock := time.Unix(0, 0)
+	c := NewEnvironmentCache(CheckEnvironment, 30*time.Second)
+	c.now = func() time.Time { return clock }
+	n := len(DefaultTools)
+
+	steps := []struct {
+		name    string
+		advance time.Duration
+		inval   bool
+		want    int
+	}{
+		{"first call", 0, false, n},
+		{"within TTL", 29 * time.Second, false, n},
+		{"after TTL", time.Second, false, 2 * n},
+		{"after Invalidate", 0, true, 3 * n},
+		{"cached again", 0, false, 3 * n},
+	}
+	for _, st := range steps {
+		clock = clock.Add(st.advance)
+		if st.inval {
+			c.Invalidate()
+		}
+		if _, err := c.Check(); err != nil {
+			t.Fatalf("%s: Check() error = %v", st.name, err)
+		}
+		if got := countRuns(t, runs); got != st.want {
+			t.Errorf("%s: stubs have run %d times, want %d", st.name, got, st.want)
+		}
+	}
+}
+
+func TestCachedEnvironmentReturnsCopies(t *testing.T) {
+	c := NewEnvironmentCache(func() ([]ToolStatus, error) {
+		return []ToolStatus{{Name: "go", Constraint: &VersionConstraint{Required: "1.22.0"}}}, nil
+	}, time.Minute)
+	first, err := c.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	first[0].Name = "changed"
+	first[0].Constraint.Required = "changed"
+	second, err := c.Check()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second[0].Name != "go" || second[0].Constraint.Required != "1.22.0" {
+		t.Errorf("after changing a returned status the cache holds %+v, %+v", second[0], *second[0].Constraint)
+	}
+}
+
+// blockingCheck returns a check that counts its calls and blocks until
+// release is closed.
+func blockingCheck(calls *atomic.Int32, release <-chan struct{}) func() ([]ToolStatus, error) {
+	return func() ([]ToolStatus, error) {
+		calls.Add(1)
+		<-release
+		return []ToolStatus{{Name: "go", Available: true}}, nil
+	}
+}
+
+func TestEnvironmentCacheZeroTTLSharesCheckInFlight(t *testing.T) {
+	const callers = 10
+	var calls, arrived atomic.Int32
+	// The check runs until every caller has called Check, and a while
+	// longer so that each has joined it.
+	c := NewEnvironmentCache(func() ([]ToolStatus, error) {
+		calls.Add(1)
+		for arrived.Load() < callers {
+			time.Sleep(time.Millisecond)
+		}
+		time.Sleep(50 * time.Millisecond)
+		return []ToolStatus{{Name: "go", Available: true}}, nil
+	}, 0)
+
+	var wg sync.WaitGroup
+	for range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			arrived.Add(1)
+			if statuses, err := c.Check(); err != nil || len(statuses) != 1 {
+				t.Errorf("Check() = %v, %v; want the shared check's result", statuses, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("check ran %d times for %d concurrent callers, want 1", got, callers)
+	}
+
+	// With nothing cached, the next call checks again.
+	if _, err := c.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("check ran %d times after a later call, want 2", got)
+	}
+}
+
+func TestEnvironmentCacheInvalidateDuringCheck(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := NewEnvironmentCache(blockingCheck(&calls, release), time.Minute)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Check()
+		done <- err
+	}()
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	c.Invalidate()
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Check(); err != nil {
+		t.Fatal(err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("check ran %d times, want the result from before Invalidate discarded", got)
+	}
+}
+
+func TestEnvironmentCacheDoesNotCacheErrors(t *testing.T) {
+	calls := 0
+	c := NewEnvironmentCache(func() ([]ToolStatus, error) {
+		calls++
+		return nil, errors.New("boom")
+	}, time.Minute)
+	for range 2 {
+		if _, err := c.Check(); err == nil {
+			t.Error("Check() error = nil, want boom")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("check ran %d times, want the failure retried", calls)
+	}
+}
+
+func TestEnvironmentCacheSetTTL(t *testing.T) {
+	calls := 0
+	c := NewEnvironmentCache(func() ([]ToolStatus, error) {
+		calls++
+		return nil, nil
+	}, time.Minute)
+	c.Check()
+	c.SetTTL(0)
+	c.Check()
+	if calls != 2 {
+		t.Errorf("check ran %d times, want the cached result expired by SetTTL(0)", calls)
+	}
+}
+
+func TestCachedEnvironmentZeroTTL(t *testing.T) {
+	runs := stubDefaultTools(t)
+	check := CachedEnvironment(0)
+	for range 2 {
+		if _, err := check(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := countRuns(t, runs), 2*len(DefaultTools); got != want {
+		t.Errorf("stubs ran %d times over two calls with no TTL, want %d", got, want)
+	}
+}
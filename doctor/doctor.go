@@ -0,0 +1,241 @@
+// Package doctor inspects the development environment and reports which of
+// the bundled tools are installed and at what version.
+package doctor
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ericbfriday/claude-go-containers/internal/procgroup"
+	"github.com/ericbfriday/claude-go-containers/internal/shellquote"
+)
+
+// DefaultTimeout bounds how long a single version probe may run.
+const DefaultTimeout = 5 * time.Second
+
+// DefaultConcurrency is how many version probes run at once when
+// Checker.Concurrency is zero.
+const DefaultConcurrency = 4
+
+// Tool describes an executable the environment is expected to provide.
+type Tool struct {
+	Name        string   // executable name looked up on PATH
+	VersionArgs []string // arguments that make the tool print its version
+	Required    bool     // whether a missing tool fails the check
+}
+
+// DefaultTools lists the tools shipped in the dev container.
+var DefaultTools = []Tool{
+	{Name: "go", VersionArgs: []string{"version"}, Required: true},
+	{Name: "claude", VersionArgs: []string{"--version"}, Required: true},
+	{Name: "opencode", VersionArgs: []string{"--version"}, Required: true},
+}
+
+// ToolStatus is the result of probing a single tool.
+type ToolStatus struct {
+	Name      string `json:"name"`
+	Path      string `json:"path"`
+	Version   string `json:"version"`
+	Available bool   `json:"available"`
+	Required  bool   `json:"required"`
+	Error     string `json:"error,omitempty"`
+	// Constraint is the result of the minimum version check, if one
+	// applied to the tool.
+	Constraint *VersionConstraint `json:"constraint,omitempty"`
+}
+
+// CheckEnvironment probes DefaultTools concurrently with DefaultTimeout per
+// tool and returns their statuses sorted by name.
+//
+// A missing or broken tool is reported with Available set to false rather
+// than as an error; the error is reserved for failures of the check itself.
+func CheckEnvironment() ([]ToolStatus, error) {
+	var c Checker
+	return c.Check(context.Background())
+}
+
+// Checker probes a set of tools. The zero value checks DefaultTools with
+// DefaultTimeout and DefaultConcurrency and does not log.
+type Checker struct {
+	Tools   []Tool        // tools to probe; nil means DefaultTools
+	Timeout time.Duration // limit for each version probe; 0 means DefaultTimeout
+	// Concurrency is the most probes run at once; 0 means
+	// DefaultConcurrency.
+	Concurrency int
+	Logger      *slog.Logger // receives one debug record per probe; nil discards
+	// DryRun, if set, receives the shell command line of each version
+	// probe, in name order, instead of it being run. Tools found on PATH
+	// are then reported as available with an empty version.
+	DryRun io.Writer
+	// MinGo, if set, is the oldest acceptable Go version, such as "1.22".
+	// A go tool reporting an older version, or one that cannot be parsed,
+	// is marked unavailable. It is not checked in dry-run mode.
+	MinGo string
+}
+
+// Check probes the tools concurrently, up to Concurrency at a time, each
+// within its own Timeout so a slow tool does not hold up the others, and
+// returns their statuses sorted by name, whatever order the probes finish
+// in.
+//
+// It returns ctx.Err() if ctx is done before the probes start or before
+// some of them could, an error naming a tool and wrapping
+// context.Cause(ctx) if ctx ends while that tool's probe runs, and an
+// error if MinGo cannot be parsed. The statuses of the probes that
+// finished are returned with either context error.
+func (c *Checker) Check(ctx context.Context) ([]ToolStatus, error) {
+	var minGo *GoVersion
+	if c.MinGo != "" {
+		v, err := ParseGoVersion(c.MinGo)
+		if err != nil {
+			return nil, fmt.Errorf("doctor: minimum Go version: %w", err)
+		}
+		minGo = &v
+	}
+	tools := c.Tools
+	if tools == nil {
+		tools = DefaultTools
+	}
+	if err := ctx.Err(); err != nil {
+		return []ToolStatus{}, err
+	}
+	tools = slices.Clone(tools)
+	slices.SortStableFunc(tools, func(a, b Tool) int { return cmp.Compare(a.Name, b.Name) })
+
+	// Each probe fills in only its own element of results.
+	type result struct {
+		status      ToolStatus
+		done        bool // the probe finished before ctx was done
+		interrupted bool // ctx ended while the probe ran
+	}
+	results := make([]result, len(tools))
+	var g errgroup.Group
+	if c.DryRun != nil {
+		// Nothing is run, so there is nothing to wait for in parallel, and
+		// probing in order writes the command lines in order.
+		g.SetLimit(1)
+	} else {
+		g.SetLimit(cmp.Or(c.Concurrency, DefaultConcurrency))
+	}
+	for i, tool := range tools {
+		g.Go(func() error {
+			if ctx.Err() != nil {
+				return nil
+			}
+			status := c.probe(ctx, tool)
+			if ctx.Err() != nil {
+				results[i].interrupted = true
+				return nil
+			}
+			if tool.Name == "go" && minGo != nil && status.Available && c.DryRun == nil {
+				checkMinGo(&status, *minGo)
+			}
+			results[i] = result{status: status, done: true}
+			return nil
+		})
+	}
+	g.Wait()
+
+	statuses := make([]ToolStatus, 0, len(tools))
+	var interrupted string
+	for i, r := range results {
+		switch {
+		case r.done:
+			statuses = append(statuses, r.status)
+		case r.interrupted && interrupted == "":
+			interrupted = tools[i].Name
+		}
+	}
+	if interrupted != "" {
+		return statuses, fmt.Errorf("%s version check: %w", interrupted, context.Cause(ctx))
+	}
+	if len(statuses) < len(tools) {
+		return statuses, ctx.Err()
+	}
+	return statuses, nil
+}
+
+// Missing returns the names of required tools that are not available.
+func Missing(statuses []ToolStatus) []string {
+	var missing []string
+	for _, s := range statuses {
+		if s.Required && !s.Available {
+			missing = append(missing, s.Name)
+		}
+	}
+	return missing
+}
+
+func (c *Checker) probe(ctx context.Context, tool Tool) ToolStatus {
+	status := ToolStatus{Name: tool.Name, Required: tool.Required}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	logger := c.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	path, err := exec.LookPath(tool.Name)
+	if err != nil {
+		status.Error = "not found on PATH"
+		logger.Debug("tool not found", "tool", tool.Name)
+		return status
+	}
+	status.Path = path
+
+	if c.DryRun != nil {
+		line := shellquote.Join(append([]string{path}, tool.VersionArgs...)...)
+		logger.Info("dry run", "command", line)
+		if _, err := fmt.Fprintln(c.DryRun, line); err != nil {
+			status.Error = err.Error()
+			return status
+		}
+		status.Available = true
+		return status
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, tool.VersionArgs...)
+	cmd.Stdout = &stdout
+	procgroup.Configure(cmd, procgroup.DefaultGrace)
+	start := time.Now()
+	err = cmd.Run()
+	logger.Debug("exec", "bin", path, "args", tool.VersionArgs, "duration", time.Since(start))
+	if err != nil {
+		logger.Error("version probe failed", "bin", path, "args", tool.VersionArgs, "err", err)
+		if ctx.Err() == context.DeadlineExceeded {
+			status.Error = "version check timed out after " + timeout.String()
+		} else {
+			status.Error = err.Error()
+		}
+		return status
+	}
+
+	status.Version = firstLine(stdout.String())
+	status.Available = true
+	return status
+}
+
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,268 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestCheck(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "good", `echo "good 1.2.3"`)
+	stub.Write(t, dir, "noisy", `printf '\n  noisy v2\nextra\n'`)
+	stub.Write(t, dir, "broken", `exit 3`)
+	t.Setenv("PATH", dir)
+
+	tools := []Tool{
+		{Name: "good", VersionArgs: []string{"--version"}, Required: true},
+		{Name: "noisy", VersionArgs: []string{"--version"}},
+		{Name: "broken", VersionArgs: []string{"--version"}},
+		{Name: "absent", VersionArgs: []string{"--version"}, Required: true},
+	}
+	c := Checker{Tools: tools, Timeout: time.Second}
+	got, err := c.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	// Statuses come back sorted by name.
+	tests := []struct {
+		name      string
+		version   string
+		available bool
+		hasPath   bool
+	}{
+		{"absent", "", false, false},
+		{"broken", "", false, true},
+		{"good", "good 1.2.3", true, true},
+		{"noisy", "noisy v2", true, true},
+	}
+	if len(got) != len(tests) {
+		t.Fatalf("Check() returned %d statuses, want %d", len(got), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := got[i]
+			if s.Name != tt.name {
+				t.Errorf("Name = %q, want %q", s.Name, tt.name)
+			}
+			if s.Version != tt.version {
+				t.Errorf("Version = %q, want %q", s.Version, tt.version)
+			}
+			if s.Available != tt.available {
+				t.Errorf("Available = %v, want %v", s.Available, tt.available)
+			}
+			if (s.Path != "") != tt.hasPath {
+				t.Errorf("Path = %q, want present=%v", s.Path, tt.hasPath)
+			}
+			if !s.Available && s.Error == "" {
+				t.Error("Error is empty for unavailable tool")
+			}
+		})
+	}
+
+	if missing := Missing(got); len(missing) != 1 || missing[0] != "absent" {
+		t.Errorf("Missing() = %v, want [absent]", missing)
+	}
+}
+
+func TestCheckTimesOutHangingTool(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "hang", `sleep 30`)
+	stub.PrependPath(t, dir)
+
+	start := time.Now()
+	c := Checker{Tools: []Tool{{Name: "hang", Required: true}}, Timeout: 100 * time.Millisecond}
+	got, err := c.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Check() took %v, want it bounded by the timeout", elapsed)
+	}
+	if got[0].Available {
+		t.Error("hanging tool reported as available")
+	}
+}
+
+func TestCheckContextDeadlineDuringProbe(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "fast", `echo "fast 1.0"`)
+	stub.Write(t, dir, "hang", `sleep 30`)
+	stub.PrependPath(t, dir)
+
+	cause := errors.New("overall deadline")
+	ctx, cancel := context.WithTimeoutCause(context.Background(), 200*time.Millisecond, cause)
+	defer cancel()
+	c := Checker{Tools: []Tool{{Name: "fast"}, {Name: "hang"}, {Name: "never"}}}
+	got, err := c.Check(ctx)
+	if !errors.Is(err, cause) || !strings.HasPrefix(err.Error(), "hang version check: ") {
+		t.Errorf("Check() error = %v, want it to name hang and wrap the context's cause", err)
+	}
+	if len(got) != 2 || got[0].Name != "fast" || got[1].Name != "never" {
+		t.Errorf("Check() = %+v, want only the tools whose probes finished before the deadline", got)
+	}
+}
+
+func TestCheckSortedRegardlessOfCompletion(t *testing.T) {
+	dir := t.TempDir()
+	// Probes finish in the reverse of name order: zeta first, alpha last.
+	var tools []Tool
+	for i, name := range []string{"zeta", "mu", "delta", "alpha"} {
+		stub.Write(t, dir, name, "sleep 0."+strconv.Itoa(i)+"; echo "+name+" 1.0")
+		tools = append(tools, Tool{Name: name})
+	}
+	t.Setenv("PATH", dir)
+
+	c := Checker{Tools: tools, Timeout: 5 * time.Second, Concurrency: len(tools)}
+	got, err := c.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, st := range got {
+		names = append(names, st.Name)
+		if st.Version != st.Name+" 1.0" {
+			t.Errorf("%s version = %q, want its own probe's output", st.Name, st.Version)
+		}
+	}
+	if want := []string{"alpha", "delta", "mu", "zeta"}; !slices.Equal(names, want) {
+		t.Errorf("Check() order = %q, want %q", names, want)
+	}
+	if tools[0].Name != "zeta" {
+		t.Error("Check() reordered the caller's Tools")
+	}
+}
+
+func TestCheckConcurrent(t *testing.T) {
+	dir := t.TempDir()
+	const n, limit = 8, 4
+	for _, sub := range []string{"started", "done"} {
+		if err := os.Mkdir(filepath.Join(dir, sub), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	running := filepath.Join(dir, "running")
+	// Each probe marks itself started and waits, for at most a few
+	// seconds, until another one is running too or every probe has
+	// started. It then logs how many were running and marks itself done.
+	script := `d="` + dir + `"
+touch "$d/started/$(basename "$0")"
+count() { set -- "$1"/*; [ -e "$1" ] && echo $# || echo 0; }
+i=0
+while [ $i -lt 500 ]; do
+	s=$(count "$d/started"); f=$(count "$d/done")
+	[ $((s - f)) -ge 2 ] || [ "$s" -eq ` + strconv.Itoa(n) + ` ] && break
+	sleep 0.01; i=$((i + 1))
+done
+echo $(($(count "$d/started") - $(count "$d/done"))) >> "` + running + `"
+touch "$d/done/$(basename "$0")"
+echo "$(basename "$0") 1.0"`
+	var tools []Tool
+	for i := range n {
+		name := "tool" + strconv.Itoa(i)
+		stub.Write(t, dir, name, script)
+		tools = append(tools, Tool{Name: name, Required: true})
+	}
+	stub.PrependPath(t, dir)
+
+	var dry bytes.Buffer
+	c := Checker{Tools: tools, Timeout: 10 * time.Second, Concurrency: limit}
+	got, err := c.Check(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(tools) || len(Missing(got)) != 0 {
+		t.Errorf("Check() = %+v, want every tool available", got)
+	}
+	data, err := os.ReadFile(running)
+	if err != nil {
+		t.Fatal(err)
+	}
+	peak := 0
+	for _, f := range strings.Fields(string(data)) {
+		k, err := strconv.Atoi(f)
+		if err != nil {
+			t.Fatalf("probe logged %q, want a count", f)
+		}
+		peak = max(peak, k)
+	}
+	if peak < 2 || peak > limit {
+		t.Errorf("at most %d probes ran at once, want between 2 and %d", peak, limit)
+	}
+
+	// Dry runs write their command lines in name order.
+	c = Checker{Tools: tools, DryRun: &dry, Concurrency: len(tools)}
+	if _, err := c.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(dry.String()), "\n")
+	if len(lines) != len(tools) || !slices.IsSorted(lines) {
+		t.Errorf("dry run wrote\n%s\nwant one line per tool, sorted", dry.String())
+	}
+}
+
+func TestCheckDryRun(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	bin := stub.Write(t, dir, "tool", `touch "`+marker+`"; echo "tool 1.0"`)
+	t.Setenv("PATH", dir)
+
+	var out bytes.Buffer
+	c := Checker{
+		Tools:  []Tool{{Name: "tool", VersionArgs: []string{"version", "--short"}}, {Name: "absent"}},
+		DryRun: &out,
+	}
+	got, err := c.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if want := bin + " version --short\n"; out.String() != want {
+		t.Errorf("dry-run output = %q, want %q", out.String(), want)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("tool was executed in dry-run mode")
+	}
+	if got[0].Available || !got[1].Available || got[1].Version != "" {
+		t.Errorf("Check() = %+v, want tool available without a version and absent missing", got)
+	}
+}
+
+func TestCheckCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var c Checker
+	if _, err := c.Check(ctx); err != context.Canceled {
+		t.Errorf("Check() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestCheckLogsProbes(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "good", `echo "good 1.0"`)
+	t.Setenv("PATH", dir)
+
+	var buf bytes.Buffer
+	c := Checker{
+		Tools:  []Tool{{Name: "good", VersionArgs: []string{"-v"}}},
+		Logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	}
+	if _, err := c.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"msg=exec", "bin=" + dir, "args=[-v]", "duration="} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log output %q does not contain %q", buf.String(), want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package doctor
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ericbfriday/claude-go-containers/internal/table"
+)
+
+// Formats lists the formats FormatToolStatus accepts.
+var Formats = []string{"table", "csv", "json"}
+
+// State summarizes st for display: "ok" if the tool is available,
+// "missing" if it is not on PATH, and "error: " followed by the probe's
+// error if it was found but could not report its version.
+func (st ToolStatus) State() string {
+	switch {
+	case st.Available:
+		return "ok"
+	case st.Path == "":
+		return "missing"
+	default:
+		return "error: " + st.Error
+	}
+}
+
+// TableRows returns the rows of the table format: a TOOL, STATUS,
+// VERSION, PATH header followed by one row per status.
+func TableRows(statuses []ToolStatus) [][]string {
+	rows := [][]string{{"TOOL", "STATUS", "VERSION", "PATH"}}
+	for _, st := range statuses {
+		rows = append(rows, []string{st.Name, st.State(), st.Version, st.Path})
+	}
+	return rows
+}
+
+// FormatToolStatus writes statuses to w in format, which must be one of
+// Formats:
+//
+//   - table: the TableRows columns, each padded to its widest value
+//   - csv: a name,path,version,available header and one record per tool,
+//     quoted as needed
+//   - json: an array of ToolStatus objects
+func FormatToolStatus(w io.Writer, statuses []ToolStatus, format string) error {
+	switch format {
+	case "table":
+		return table.Write(w, TableRows(statuses), nil)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"name", "path", "version", "available"})
+		for _, st := range statuses {
+			cw.Write([]string{st.Name, st.Path, st.Version, strconv.FormatBool(st.Available)})
+		}
+		cw.Flush()
+		return cw.Error()
+	case "json":
+		if statuses == nil {
+			statuses = []ToolStatus{}
+		}
+		return json.NewEncoder(w).Encode(statuses)
+	default:
+		return fmt.Errorf("unknown format %q: want table, csv or json", format)
+	}
+}
@@ -0,0 +1,64 @@
+package doctor
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var formatStatuses = []ToolStatus{
+	{Name: "go", Path: "/usr/local/go/bin/go", Version: "go version go1.24.9 linux/amd64", Available: true, Required: true},
+	{Name: "claude", Path: "/opt/tools, v2/claude", Error: "exit status 1: bad config", Required: true},
+	{Name: "opencode", Required: true},
+}
+
+func TestFormatToolStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []ToolStatus
+		format   string
+	}{
+		{"table", formatStatuses, "table"},
+		{"csv", formatStatuses, "csv"},
+		{"json", formatStatuses, "json"},
+		{"empty_table", nil, "table"},
+		{"empty_csv", nil, "csv"},
+		{"empty_json", nil, "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := FormatToolStatus(&b, tt.statuses, tt.format); err != nil {
+				t.Fatalf("FormatToolStatus() error = %v", err)
+			}
+			golden := filepath.Join("testdata", tt.name+".golden")
+			if *update {
+				if err := os.WriteFile(golden, b.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(b.Bytes(), want) {
+				t.Errorf("FormatToolStatus(%q) =\n%s\nwant (%s):\n%s", tt.format, b.Bytes(), golden, want)
+			}
+		})
+	}
+}
+
+func TestFormatToolStatusUnknownFormat(t *testing.T) {
+	var b bytes.Buffer
+	if err := FormatToolStatus(&b, formatStatuses, "yaml"); err == nil {
+		t.Error("FormatToolStatus(yaml) error = nil")
+	}
+	if b.Len() != 0 {
+		t.Errorf("wrote %q for an unknown format", b.String())
+	}
+}
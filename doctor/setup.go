@@ -0,0 +1,75 @@
+package doctor
+
+// Requirement is a tool the environment should provide, with the command
+// that installs it. Setup only ever prints Install; it never runs it.
+type Requirement struct {
+	Name     string `json:"name"`     // executable name looked up on PATH
+	Required bool   `json:"required"` // whether a missing tool fails the verification
+	Install  string `json:"install"`  // shell command, or instructions, that install the tool
+}
+
+// DefaultRequirements lists the tools the dev container image provides.
+// Go comes with its golang base image, so its entry points to the
+// downloads for every platform; the others are installed by the
+// Dockerfile, with the commands given here. The AI tools and Go itself
+// are required; the Go developer tools are optional.
+var DefaultRequirements = []Requirement{
+	{Name: "go", Required: true, Install: "download and install Go from https://go.dev/dl/"},
+	{Name: "claude", Required: true, Install: "npm install -g @anthropic-ai/claude-code"},
+	{Name: "opencode", Required: true, Install: "npm install -g opencode-ai@latest"},
+	{Name: "gopls", Install: "go install golang.org/x/tools/gopls@latest"},
+	{Name: "dlv", Install: "go install github.com/go-delve/delve/cmd/dlv@latest"},
+	{Name: "staticcheck", Install: "go install honnef.co/go/tools/cmd/staticcheck@latest"},
+	{Name: "goimports", Install: "go install golang.org/x/tools/cmd/goimports@latest"},
+}
+
+// RequirementStatus is the result of verifying a Requirement.
+type RequirementStatus struct {
+	Requirement
+	Path  string `json:"path,omitempty"`
+	Found bool   `json:"found"`
+}
+
+// State summarizes st for display: "ok" if the tool was found, "missing"
+// if a required tool was not and "warning" if an optional one was not.
+func (st RequirementStatus) State() string {
+	switch {
+	case st.Found:
+		return "ok"
+	case st.Required:
+		return "missing"
+	default:
+		return "warning"
+	}
+}
+
+// Verify looks each requirement up with lookPath, normally exec.LookPath,
+// and reports whether it was found, in order. overrides, keyed by tool
+// name, replace the Install commands of the matching requirements.
+// Nothing is run, so verifying is always safe to repeat.
+func Verify(reqs []Requirement, overrides map[string]string, lookPath func(string) (string, error)) []RequirementStatus {
+	statuses := make([]RequirementStatus, len(reqs))
+	for i, req := range reqs {
+		if cmd, ok := overrides[req.Name]; ok {
+			req.Install = cmd
+		}
+		st := RequirementStatus{Requirement: req}
+		if path, err := lookPath(req.Name); err == nil {
+			st.Path, st.Found = path, true
+		}
+		statuses[i] = st
+	}
+	return statuses
+}
+
+// MissingRequirements returns the names of the required tools that were
+// not found.
+func MissingRequirements(statuses []RequirementStatus) []string {
+	var missing []string
+	for _, st := range statuses {
+		if st.Required && !st.Found {
+			missing = append(missing, st.Name)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,81 @@
+package doctor
+
+import (
+	"errors"
+	"os/exec"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestDefaultRequirements(t *testing.T) {
+	var required []string
+	seen := make(map[string]bool)
+	for _, req := range DefaultRequirements {
+		if seen[req.Name] {
+			t.Errorf("%s is listed twice", req.Name)
+		}
+		seen[req.Name] = true
+		if req.Install == "" {
+			t.Errorf("%s has no install command", req.Name)
+		}
+		if req.Required {
+			required = append(required, req.Name)
+		}
+	}
+	if want := []string{"go", "claude", "opencode"}; !slices.Equal(required, want) {
+		t.Errorf("required tools = %q, want %q", required, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	reqs := []Requirement{
+		{Name: "go", Required: true, Install: "install go"},
+		{Name: "claude", Required: true, Install: "install claude"},
+		{Name: "gopls", Install: "install gopls"},
+		{Name: "dlv", Install: "install dlv"},
+	}
+	onPath := map[string]string{"go": "/usr/bin/go", "dlv": "/go/bin/dlv"}
+	lookPath := func(name string) (string, error) {
+		if path, ok := onPath[name]; ok {
+			return path, nil
+		}
+		return "", exec.ErrNotFound
+	}
+
+	got := Verify(reqs, map[string]string{"claude": "brew install claude", "other": "ignored"}, lookPath)
+	want := []RequirementStatus{
+		{Requirement: reqs[0], Path: "/usr/bin/go", Found: true},
+		{Requirement: Requirement{Name: "claude", Required: true, Install: "brew install claude"}},
+		{Requirement: reqs[2]},
+		{Requirement: reqs[3], Path: "/go/bin/dlv", Found: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Verify() =\n%+v\nwant\n%+v", got, want)
+	}
+	if reqs[1].Install != "install claude" {
+		t.Error("Verify() modified its requirements")
+	}
+
+	if missing := MissingRequirements(got); !slices.Equal(missing, []string{"claude"}) {
+		t.Errorf("MissingRequirements() = %q, want only the required claude", missing)
+	}
+	states := make([]string, len(got))
+	for i, st := range got {
+		states[i] = st.State()
+	}
+	if want := []string{"ok", "missing", "warning", "ok"}; !slices.Equal(states, want) {
+		t.Errorf("states = %q, want %q", states, want)
+	}
+}
+
+func TestVerifyNothingMissing(t *testing.T) {
+	found := func(name string) (string, error) { return "/bin/" + name, nil }
+	if missing := MissingRequirements(Verify(DefaultRequirements, nil, found)); missing != nil {
+		t.Errorf("MissingRequirements() = %q, want none", missing)
+	}
+	notFound := func(string) (string, error) { return "", errors.New("no") }
+	if missing := MissingRequirements(Verify(DefaultRequirements, nil, notFound)); len(missing) != 3 {
+		t.Errorf("MissingRequirements() = %q, want the three required tools", missing)
+	}
+}
@@ -0,0 +1,115 @@
+package doctor
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// GoVersion is a Go release such as 1.22.3 or a pre-release such as
+// 1.23rc1.
+type GoVersion struct {
+	Major, Minor, Patch int
+	Pre                 string // "beta" or "rc" for a pre-release, else empty
+	PreNum              int    // the number following Pre
+}
+
+// goVersionRE matches a version like 1.22, go1.22.3 or go1.23rc1 at the
+// start of its input or of a word.
+var goVersionRE = regexp.MustCompile(`(?:^|[^\w.])(?:go)?(\d+)\.(\d+)(?:\.(\d+))?(?:(beta|rc)(\d+))?`)
+
+// ParseGoVersion extracts the first Go version from s, which may be the
+// output of go version ("go version go1.22.3 linux/amd64") or a bare
+// version such as "1.22" or "go1.23rc1".
+func ParseGoVersion(s string) (GoVersion, error) {
+	m := goVersionRE.FindStringSubmatch(s)
+	if m == nil {
+		return GoVersion{}, fmt.Errorf("no Go version in %q", s)
+	}
+	atoi := func(s string) int {
+		n, _ := strconv.Atoi(s) // digits only, by the pattern
+		return n
+	}
+	return GoVersion{Major: atoi(m[1]), Minor: atoi(m[2]), Patch: atoi(m[3]), Pre: m[4], PreNum: atoi(m[5])}, nil
+}
+
+// String formats v the way Go names its releases, without the "go"
+// prefix: "1.22.3", or "1.23rc1" for a pre-release.
+func (v GoVersion) String() string {
+	if v.Pre != "" {
+		return fmt.Sprintf("%d.%d%s%d", v.Major, v.Minor, v.Pre, v.PreNum)
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Compare returns -1, 0 or +1 as v is older than, the same as or newer
+// than w. Pre-releases come before the release they lead up to, betas
+// before release candidates: 1.23beta1 < 1.23rc1 < 1.23.0 < 1.23.1.
+func (v GoVersion) Compare(w GoVersion) int {
+	return cmp.Or(
+		cmp.Compare(v.Major, w.Major),
+		cmp.Compare(v.Minor, w.Minor),
+		cmp.Compare(v.Patch, w.Patch),
+		cmp.Compare(preRank(v.Pre), preRank(w.Pre)),
+		cmp.Compare(v.PreNum, w.PreNum),
+	)
+}
+
+func preRank(pre string) int {
+	switch pre {
+	case "beta":
+		return 0
+	case "rc":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// VersionConstraint records a minimum version check. Required and Actual
+// are formatted with GoVersion.String; Actual is empty if the version
+// could not be parsed.
+type VersionConstraint struct {
+	Required  string `json:"required"`
+	Actual    string `json:"actual"`
+	Satisfied bool   `json:"satisfied"`
+}
+
+// ApplyMinGo checks the go tool in statuses against the minimum version
+// min, as Checker.MinGo does, for statuses obtained some other way, such
+// as from an EnvironmentCache. The go status is modified in place; if go
+// is missing or unavailable, nothing changes.
+func ApplyMinGo(statuses []ToolStatus, min string) error {
+	v, err := ParseGoVersion(min)
+	if err != nil {
+		return fmt.Errorf("doctor: minimum Go version: %w", err)
+	}
+	for i := range statuses {
+		if statuses[i].Name == "go" && statuses[i].Available {
+			checkMinGo(&statuses[i], v)
+		}
+	}
+	return nil
+}
+
+// checkMinGo compares the version reported by go version against min and
+// records the result on status, marking it unavailable if the version is
+// older or cannot be determined.
+func checkMinGo(status *ToolStatus, min GoVersion) {
+	c := &VersionConstraint{Required: min.String()}
+	status.Constraint = c
+	actual, err := ParseGoVersion(status.Version)
+	if err != nil {
+		status.Available = false
+		status.Error = "cannot check minimum version: " + err.Error()
+		return
+	}
+	c.Actual = actual.String()
+	if actual.Compare(min) < 0 {
+		status.Available = false
+		status.Error = fmt.Sprintf("go %s is older than the required %s", c.Actual, c.Required)
+		return
+	}
+	c.Satisfied = true
+}
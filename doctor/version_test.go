@@ -0,0 +1,111 @@
+package doctor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestParseGoVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    GoVersion
+		wantErr bool
+	}{
+		{"go version go1.22.3 linux/amd64", GoVersion{Major: 1, Minor: 22, Patch: 3}, false},
+		{"go version go1.21 darwin/arm64", GoVersion{Major: 1, Minor: 21}, false},
+		{"go version go1.23rc1 linux/amd64", GoVersion{Major: 1, Minor: 23, Pre: "rc", PreNum: 1}, false},
+		{"go version go1.24beta2 windows/amd64", GoVersion{Major: 1, Minor: 24, Pre: "beta", PreNum: 2}, false},
+		{"go version go1.22.0-X:boringcrypto linux/amd64", GoVersion{Major: 1, Minor: 22}, false},
+		{"go version devel go1.25-abcdef Tue Jan 1 linux/amd64", GoVersion{Major: 1, Minor: 25}, false},
+		{"1.22", GoVersion{Major: 1, Minor: 22}, false},
+		{"go1.22.10", GoVersion{Major: 1, Minor: 22, Patch: 10}, false},
+		{"", GoVersion{}, true},
+		{"go version unknown", GoVersion{}, true},
+		{"v1", GoVersion{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseGoVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseGoVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseGoVersion(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGoVersionCompare(t *testing.T) {
+	// Each version is newer than the one before it.
+	ordered := []string{"1.20", "1.21beta1", "1.21rc1", "1.21rc2", "1.21.0", "1.21.1", "1.21.10", "1.22rc1", "1.22.0", "2.0"}
+	for i, a := range ordered {
+		va, _ := ParseGoVersion(a)
+		for j, b := range ordered {
+			vb, _ := ParseGoVersion(b)
+			want := 0
+			if i < j {
+				want = -1
+			} else if i > j {
+				want = 1
+			}
+			if got := va.Compare(vb); got != want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestGoVersionString(t *testing.T) {
+	for input, want := range map[string]string{"1.22": "1.22.0", "go1.22.3": "1.22.3", "go1.23rc1": "1.23rc1"} {
+		v, _ := ParseGoVersion(input)
+		if got := v.String(); got != want {
+			t.Errorf("ParseGoVersion(%q).String() = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestCheckMinGo(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "go", `echo "go version go1.22rc1 linux/amd64"`)
+	t.Setenv("PATH", dir)
+	tools := []Tool{{Name: "go", VersionArgs: []string{"version"}, Required: true}}
+
+	tests := []struct {
+		minGo     string
+		want      VersionConstraint
+		available bool
+	}{
+		{"1.21", VersionConstraint{Required: "1.21.0", Actual: "1.22rc1", Satisfied: true}, true},
+		{"1.22rc1", VersionConstraint{Required: "1.22rc1", Actual: "1.22rc1", Satisfied: true}, true},
+		{"1.22", VersionConstraint{Required: "1.22.0", Actual: "1.22rc1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.minGo, func(t *testing.T) {
+			c := Checker{Tools: tools, Timeout: time.Second, MinGo: tt.minGo}
+			got, err := c.Check(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			st := got[0]
+			if st.Constraint == nil || *st.Constraint != tt.want {
+				t.Errorf("Constraint = %+v, want %+v", st.Constraint, tt.want)
+			}
+			if st.Available != tt.available {
+				t.Errorf("Available = %v, want %v (error %q)", st.Available, tt.available, st.Error)
+			}
+			if !tt.available && len(Missing(got)) != 1 {
+				t.Errorf("Missing() = %v, want go reported", Missing(got))
+			}
+		})
+	}
+
+	if _, err := (&Checker{Tools: tools, MinGo: "latest"}).Check(context.Background()); err == nil {
+		t.Error("Check() with an unparsable MinGo error = nil")
+	}
+}
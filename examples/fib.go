@@ -0,0 +1,45 @@
+package examples
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrNegative is returned by functions that are only defined for
+// non-negative input
+var ErrNegative = errors.New("negative input")
+
+// MaxFibonacci is the largest n for which Fibonacci(n) fits in a uint64
+const MaxFibonacci = 93
+
+// Fibonacci returns the nth Fibonacci number, with Fibonacci(0) = 0 and
+// Fibonacci(1) = 1. It returns ErrNegative for n < 0 and ErrOverflow for
+// n > MaxFibonacci. The loop keeps only the last two values, so it runs in
+// O(n) time and constant space, unlike the textbook double recursion.
+func Fibonacci(n int) (uint64, error) {
+	switch {
+	case n < 0:
+		return 0, ErrNegative
+	case n > MaxFibonacci:
+		return 0, ErrOverflow
+	}
+	var a, b uint64 = 0, 1
+	for range n {
+		a, b = b, a+b
+	}
+	return a, nil
+}
+
+// FibonacciBig is like Fibonacci but computes with math/big, so any
+// non-negative n is accepted.
+func FibonacciBig(n int) (*big.Int, error) {
+	if n < 0 {
+		return nil, ErrNegative
+	}
+	a, b := big.NewInt(0), big.NewInt(1)
+	for range n {
+		a.Add(a, b)
+		a, b = b, a
+	}
+	return a, nil
+}
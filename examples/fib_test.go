@@ -0,0 +1,79 @@
+package examples
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestFibonacci(t *testing.T) {
+	tests := []struct {
+		n        int
+		expected uint64
+		err      error
+	}{
+		{0, 0, nil},
+		{1, 1, nil},
+		{2, 1, nil},
+		{10, 55, nil},
+		{50, 12586269025, nil},
+		{MaxFibonacci, 12200160415121876738, nil},
+		{MaxFibonacci + 1, 0, ErrOverflow},
+		{-1, 0, ErrNegative},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			result, err := Fibonacci(tt.n)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Fibonacci(%d) error = %v, want %v", tt.n, err, tt.err)
+			}
+			if result != tt.expected {
+				t.Errorf("Fibonacci(%d) = %d, want %d", tt.n, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFibonacciOverflowBoundary(t *testing.T) {
+	// F(94) = F(93) + F(92) must be the first value past math.MaxUint64.
+	f93, _ := Fibonacci(MaxFibonacci)
+	f92, _ := Fibonacci(MaxFibonacci - 1)
+	if f92 <= math.MaxUint64-f93 {
+		t.Errorf("F(%d) + F(%d) fits in a uint64; MaxFibonacci is too small", MaxFibonacci, MaxFibonacci-1)
+	}
+	big94, _ := FibonacciBig(MaxFibonacci + 1)
+	if big94.IsUint64() {
+		t.Errorf("FibonacciBig(%d) = %v fits in a uint64", MaxFibonacci+1, big94)
+	}
+}
+
+func TestFibonacciBigAgrees(t *testing.T) {
+	for n := 0; n <= MaxFibonacci; n++ {
+		want, err := Fibonacci(n)
+		if err != nil {
+			t.Fatalf("Fibonacci(%d) error = %v", n, err)
+		}
+		got, err := FibonacciBig(n)
+		if err != nil {
+			t.Fatalf("FibonacciBig(%d) error = %v", n, err)
+		}
+		if !got.IsUint64() || got.Uint64() != want {
+			t.Errorf("FibonacciBig(%d) = %v, Fibonacci(%d) = %d", n, got, n, want)
+		}
+	}
+}
+
+func TestFibonacciBig(t *testing.T) {
+	got, err := FibonacciBig(100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "354224848179261915075"; got.String() != want {
+		t.Errorf("FibonacciBig(100) = %v, want %s", got, want)
+	}
+	if _, err := FibonacciBig(-1); !errors.Is(err, ErrNegative) {
+		t.Errorf("FibonacciBig(-1) error = %v, want ErrNegative", err)
+	}
+}
@@ -0,0 +1,36 @@
+package examples
+
+import "strings"
+
+// GreetGroup returns a single greeting addressed to everyone in names:
+// "Hello, Alice!", "Hello, Alice and Bob!" or, for three or more, a list
+// with a serial (Oxford) comma such as "Hello, Alice, Bob, and Carol!".
+// Names are cleaned with SanitizeName and those left blank are dropped; if
+// none remain the greeting is "Hello, everyone!".
+func GreetGroup(names []string) string {
+	clean := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = SanitizeName(name); name != "" {
+			clean = append(clean, name)
+		}
+	}
+	if len(clean) == 0 {
+		return "Hello, everyone!"
+	}
+	return "Hello, " + joinList(clean) + "!"
+}
+
+// joinList joins items as an English list: "a", "a and b", or
+// "a, b, and c".
+func joinList(items []string) string {
+	switch len(items) {
+	case 0:
+		return ""
+	case 1:
+		return items[0]
+	case 2:
+		return items[0] + " and " + items[1]
+	default:
+		return strings.Join(items[:len(items)-1], ", ") + ", and " + items[len(items)-1]
+	}
+}
@@ -0,0 +1,29 @@
+package examples
+
+import "testing"
+
+func TestGreetGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []string
+		expected string
+	}{
+		{"no names", nil, "Hello, everyone!"},
+		{"one name", []string{"Alice"}, "Hello, Alice!"},
+		{"two names", []string{"Alice", "Bob"}, "Hello, Alice and Bob!"},
+		{"three names", []string{"Alice", "Bob", "Carol"}, "Hello, Alice, Bob, and Carol!"},
+		{"five names", []string{"Alice", "Bob", "Carol", "Dave", "Eve"}, "Hello, Alice, Bob, Carol, Dave, and Eve!"},
+		{"blank names are dropped", []string{" ", "Alice", "", "Bob"}, "Hello, Alice and Bob!"},
+		{"only blank names", []string{"", "\t"}, "Hello, everyone!"},
+		{"names are sanitized", []string{" Alice\x1b ", "Bob\n"}, "Hello, Alice and Bob!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GreetGroup(tt.input)
+			if result != tt.expected {
+				t.Errorf("GreetGroup(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
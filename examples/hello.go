@@ -1,16 +1,100 @@
 package examples
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
-// Greet returns a greeting message
+	"github.com/ericbfriday/claude-go-containers/progress"
+)
+
+// now returns the current time. Tests replace it to pin the time of day.
+var now = time.Now
+
+// Greet returns a greeting message appropriate for the current time of day
 func Greet(name string) string {
+	return GreetAt(name, now())
+}
+
+// GreetAt returns a greeting message for name at time t. The salutation
+// depends on the hour of t: "Good morning" from 05:00, "Good afternoon"
+// from 12:00, "Good evening" from 17:00 and "Hello" from 22:00 until 05:00.
+// The name is cleaned with SanitizeName first.
+func GreetAt(name string, t time.Time) string {
+	name = SanitizeName(name)
 	if name == "" {
 		name = "World"
 	}
-	return fmt.Sprintf("Hello, %s!", name)
+	return fmt.Sprintf("%s, %s!", salutation(t), name)
+}
+
+// GreetAll returns one greeting per name, in the same order
+func GreetAll(names []string) []string {
+	greetings := make([]string, len(names))
+	for i, name := range names {
+		greetings[i] = Greet(name)
+	}
+	return greetings
+}
+
+// GreetAllConcurrent returns the same greetings as GreetAll, computed by up
+// to workers goroutines. The output keeps the order of names. With workers
+// of 1 or less the names are greeted serially.
+func GreetAllConcurrent(names []string, workers int) []string {
+	return GreetAllProgress(names, workers, nil)
+}
+
+// GreetAllProgress is GreetAllConcurrent reporting to p, which may be nil,
+// as each greeting is done, and finishing p before it returns.
+func GreetAllProgress(names []string, workers int, p *progress.Progress) []string {
+	defer p.Finish()
+	greetings := make([]string, len(names))
+	p.Update(0, len(names))
+	var done atomic.Int64
+	greetOne := func(i int) {
+		greetings[i] = Greet(names[i])
+		p.Update(int(done.Add(1)), len(names))
+	}
+
+	workers = min(workers, len(names))
+	if workers <= 1 {
+		for i := range names {
+			greetOne(i)
+		}
+		return greetings
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Each index is handled by exactly one worker, so writes to
+			// greetings never overlap.
+			for i := range indexes {
+				greetOne(i)
+			}
+		}()
+	}
+	for i := range names {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return greetings
 }
 
-// Add returns the sum of two integers
-func Add(a, b int) int {
-	return a + b
+func salutation(t time.Time) string {
+	switch h := t.Hour(); {
+	case h >= 5 && h < 12:
+		return "Good morning"
+	case h >= 12 && h < 17:
+		return "Good afternoon"
+	case h >= 17 && h < 22:
+		return "Good evening"
+	default:
+		return "Hello"
+	}
 }
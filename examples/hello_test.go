@@ -1,8 +1,29 @@
 package examples
 
-import "testing"
+import (
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ericbfriday/claude-go-containers/progress"
+)
+
+// setNow pins the clock used by Greet for the duration of the test.
+func setNow(t *testing.T, at time.Time) {
+	t.Helper()
+	orig := now
+	now = func() time.Time { return at }
+	t.Cleanup(func() { now = orig })
+}
 
 func TestGreet(t *testing.T) {
+	setNow(t, time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC))
+
 	tests := []struct {
 		name     string
 		input    string
@@ -23,24 +44,152 @@ func TestGreet(t *testing.T) {
 	}
 }
 
-func TestAdd(t *testing.T) {
+func TestGreetAll(t *testing.T) {
+	setNow(t, time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		input    []string
+		expected []string
+	}{
+		{"nil slice", nil, []string{}},
+		{"single name", []string{"Alice"}, []string{"Hello, Alice!"}},
+		{"keeps order and blanks", []string{"Bob", "", "Carol"}, []string{"Hello, Bob!", "Hello, World!", "Hello, Carol!"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GreetAll(tt.input)
+			if !slices.Equal(result, tt.expected) {
+				t.Errorf("GreetAll(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGreetAllConcurrent(t *testing.T) {
+	setNow(t, time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+
+	names := make([]string, 5000)
+	for i := range names {
+		names[i] = "Name" + strconv.Itoa(i)
+	}
+	names[42] = ""
+	want := GreetAll(names)
+
+	for _, workers := range []int{-1, 0, 1, 2, 8, 64, len(names) + 1} {
+		t.Run("workers="+strconv.Itoa(workers), func(t *testing.T) {
+			got := GreetAllConcurrent(names, workers)
+			if len(got) != len(want) {
+				t.Fatalf("GreetAllConcurrent(..., %d) returned %d greetings, want %d", workers, len(got), len(want))
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Fatalf("GreetAllConcurrent(..., %d)[%d] = %q, want %q", workers, i, got[i], want[i])
+				}
+			}
+		})
+	}
+
+	if got := GreetAllConcurrent(nil, 4); got == nil || len(got) != 0 {
+		t.Errorf("GreetAllConcurrent(nil, 4) = %#v, want an empty slice", got)
+	}
+}
+
+func TestGreetAllProgress(t *testing.T) {
+	setNow(t, time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+	names := []string{"Ada", "Bob", "Cy", "Di"}
+
+	for _, workers := range []int{1, 3} {
+		t.Run("workers="+strconv.Itoa(workers), func(t *testing.T) {
+			var out strings.Builder
+			p := progress.New(&out, func(io.Writer) bool { return true })
+			got := GreetAllProgress(names, workers, p)
+			if !slices.Equal(got, GreetAll(names)) {
+				t.Errorf("GreetAllProgress() = %q, want %q", got, GreetAll(names))
+			}
+			for _, want := range []string{"[0/4]", "[4/4]"} {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("progress = %q, want it to show %s", out.String(), want)
+				}
+			}
+			if !strings.HasSuffix(out.String(), "\r\x1b[K") {
+				t.Errorf("progress = %q, want the line cleared at the end", out.String())
+			}
+		})
+	}
+}
+
+func TestGreetAt(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2025, 1, 1, hour, min, 0, 0, time.UTC)
+	}
+
 	tests := []struct {
 		name     string
-		a, b     int
-		expected int
+		input    string
+		t        time.Time
+		expected string
 	}{
-		{"positive numbers", 2, 3, 5},
-		{"with zero", 0, 5, 5},
-		{"negative numbers", -2, -3, -5},
-		{"mixed signs", -2, 3, 1},
+		{"midnight", "Alice", at(0, 0), "Hello, Alice!"},
+		{"last night minute", "Alice", at(4, 59), "Hello, Alice!"},
+		{"morning starts", "Alice", at(5, 0), "Good morning, Alice!"},
+		{"last morning minute", "Alice", at(11, 59), "Good morning, Alice!"},
+		{"afternoon starts", "Alice", at(12, 0), "Good afternoon, Alice!"},
+		{"last afternoon minute", "Alice", at(16, 59), "Good afternoon, Alice!"},
+		{"evening starts", "Alice", at(17, 0), "Good evening, Alice!"},
+		{"last evening minute", "Alice", at(21, 59), "Good evening, Alice!"},
+		{"night starts", "Alice", at(22, 0), "Hello, Alice!"},
+		{"empty name", "", at(9, 30), "Good morning, World!"},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := Add(tt.a, tt.b)
+			result := GreetAt(tt.input, tt.t)
 			if result != tt.expected {
-				t.Errorf("Add(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+				t.Errorf("GreetAt(%q, %s) = %q, want %q", tt.input, tt.t.Format("15:04"), result, tt.expected)
 			}
 		})
 	}
 }
+
+func FuzzGreet(f *testing.F) {
+	for _, seed := range []string{"", "Alice", " ", "José", "東京", "👋🏽", "\xff\xfe", "a\x00b", "Bob\n\033[31mX"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		result := Greet(name)
+		want := SanitizeName(name)
+		if want == "" {
+			want = "World"
+		}
+		if !utf8.ValidString(result) {
+			t.Errorf("Greet(%q) = %q, want valid UTF-8", name, result)
+		}
+		if i := strings.IndexFunc(result, unicode.IsControl); i >= 0 {
+			t.Errorf("Greet(%q) = %q, contains control character at byte %d", name, result, i)
+		}
+		if !strings.Contains(result, want) {
+			t.Errorf("Greet(%q) = %q, want it to contain %q", name, result, want)
+		}
+		if !strings.HasSuffix(result, want+"!") {
+			t.Errorf("Greet(%q) = %q, want it to end with %q", name, result, want+"!")
+		}
+	})
+}
+
+func BenchmarkGreet(b *testing.B) {
+	for b.Loop() {
+		Greet("Alice")
+	}
+}
+
+func BenchmarkGreetAllConcurrent(b *testing.B) {
+	names := make([]string, 1000)
+	for i := range names {
+		names[i] = "Name" + strconv.Itoa(i)
+	}
+	for b.Loop() {
+		GreetAllConcurrent(names, 8)
+	}
+}
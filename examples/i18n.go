@@ -0,0 +1,44 @@
+package examples
+
+import (
+	"fmt"
+	"strings"
+)
+
+// locale holds the words needed to greet someone in one language
+type locale struct {
+	hello string
+	world string
+}
+
+// locales maps ISO 639-1 language codes to their greeting words
+var locales = map[string]locale{
+	"en": {"Hello", "World"},
+	"es": {"Hola", "Mundo"},
+	"fr": {"Bonjour", "Monde"},
+	"de": {"Hallo", "Welt"},
+}
+
+// GreetLocalized returns a greeting in the language identified by lang,
+// such as "es" or "fr-CA". Region suffixes are ignored and unknown
+// languages fall back to English. The name is cleaned with SanitizeName,
+// and an empty name is replaced by the localized word for "World".
+func GreetLocalized(name, lang string) string {
+	loc, ok := locales[baseLanguage(lang)]
+	if !ok {
+		loc = locales["en"]
+	}
+	if name = SanitizeName(name); name == "" {
+		name = loc.world
+	}
+	return fmt.Sprintf("%s, %s!", loc.hello, name)
+}
+
+// baseLanguage reduces a tag like "pt_BR" or "en-US" to its lowercase
+// language subtag.
+func baseLanguage(lang string) string {
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return strings.ToLower(lang)
+}
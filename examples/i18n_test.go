@@ -0,0 +1,37 @@
+package examples
+
+import "testing"
+
+func TestGreetLocalized(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		lang     string
+		expected string
+	}{
+		{"english", "Alice", "en", "Hello, Alice!"},
+		{"english fallback name", "", "en", "Hello, World!"},
+		{"spanish", "Alice", "es", "Hola, Alice!"},
+		{"spanish fallback name", "", "es", "Hola, Mundo!"},
+		{"french", "Alice", "fr", "Bonjour, Alice!"},
+		{"french fallback name", "", "fr", "Bonjour, Monde!"},
+		{"german", "Alice", "de", "Hallo, Alice!"},
+		{"german fallback name", "", "de", "Hallo, Welt!"},
+		{"region suffix", "Alice", "es-MX", "Hola, Alice!"},
+		{"underscore and case", "Alice", "DE_at", "Hallo, Alice!"},
+		{"unknown language", "Alice", "xx", "Hello, Alice!"},
+		{"unknown language fallback name", "", "xx", "Hello, World!"},
+		{"sanitizes name", "Al\x1bice\n", "es", "Hola, Alice!"},
+		{"control-only name falls back", "\n", "de", "Hallo, Welt!"},
+		{"empty language", "Alice", "", "Hello, Alice!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := GreetLocalized(tt.input, tt.lang)
+			if result != tt.expected {
+				t.Errorf("GreetLocalized(%q, %q) = %q, want %q", tt.input, tt.lang, result, tt.expected)
+			}
+		})
+	}
+}
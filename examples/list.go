@@ -0,0 +1,84 @@
+package examples
+
+import (
+	htmltemplate "html/template"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// ListItem is one greeting in the slice a list template is executed with
+type ListItem struct {
+	Name     string // sanitized name, "World" if empty
+	Greeting string
+}
+
+// listFormat is a built-in list template. HTML formats are executed with
+// html/template, which escapes every value for its context in the page.
+type listFormat struct {
+	tmpl string
+	html bool
+}
+
+var listFormats = map[string]listFormat{
+	"markdown": {tmpl: "{{range .}}- {{.Greeting}}\n{{end}}"},
+	"html":     {tmpl: "<ul>\n{{range .}}  <li>{{.Greeting}}</li>\n{{end}}</ul>\n", html: true},
+}
+
+// ListFormats returns the names of the built-in list templates accepted by
+// GreetList and FormatList, sorted.
+func ListFormats() []string {
+	names := make([]string, 0, len(listFormats))
+	for name := range listFormats {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// GreetList greets each of names with Greet and renders the greetings
+// together through tmpl, as FormatList does.
+func GreetList(names []string, tmpl string) (string, error) {
+	items := make([]ListItem, len(names))
+	for i, name := range names {
+		name = SanitizeName(name)
+		if name == "" {
+			name = "World"
+		}
+		items[i] = ListItem{Name: name, Greeting: Greet(name)}
+	}
+	var b strings.Builder
+	if err := FormatList(&b, items, tmpl); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// FormatList writes items to w through tmpl, which is either the name of
+// a built-in format, "markdown" for a bullet list or "html" for a <ul>
+// element, or a text/template executed with the []ListItem, such as
+// "{{range .}}{{.Name}}: {{.Greeting}}\n{{end}}". The html format escapes
+// names, so a name cannot inject markup; custom templates are not escaped.
+// An error is returned if tmpl cannot be parsed or executed.
+func FormatList(w io.Writer, items []ListItem, tmpl string) error {
+	if items == nil {
+		items = []ListItem{}
+	}
+	f, ok := listFormats[tmpl]
+	if !ok {
+		f = listFormat{tmpl: tmpl}
+	}
+	if f.html {
+		t, err := htmltemplate.New("list").Option("missingkey=error").Parse(f.tmpl)
+		if err != nil {
+			return err
+		}
+		return t.Execute(w, items)
+	}
+	t, err := template.New("list").Option("missingkey=error").Parse(f.tmpl)
+	if err != nil {
+		return err
+	}
+	return t.Execute(w, items)
+}
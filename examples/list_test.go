@@ -0,0 +1,95 @@
+package examples
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+var listNames = []string{"Ada", `<script>alert("hi")</script>`, "Tom & Jerry", "O'Brien", ""}
+
+func TestGreetListGolden(t *testing.T) {
+	setNow(t, time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+
+	for _, format := range ListFormats() {
+		t.Run(format, func(t *testing.T) {
+			got, err := GreetList(listNames, format)
+			if err != nil {
+				t.Fatalf("GreetList(%q) error = %v", format, err)
+			}
+			golden := filepath.Join("testdata", "list_"+format+".golden")
+			if *update {
+				if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != string(want) {
+				t.Errorf("GreetList(%q) =\n%s\nwant (%s):\n%s", format, got, golden, want)
+			}
+		})
+	}
+}
+
+func TestGreetListHTMLEscapes(t *testing.T) {
+	got, err := GreetList([]string{`<img src=x onerror="alert(1)">`}, "html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "<img") {
+		t.Errorf("GreetList(html) = %q, want the name's markup escaped", got)
+	}
+}
+
+func TestGreetListCustom(t *testing.T) {
+	setNow(t, time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		want    string
+		wantErr bool
+	}{
+		{"fields", "{{range .}}{{.Name}}={{.Greeting}};{{end}}", "Ada=Good morning, Ada!;World=Good morning, World!;", false},
+		{"count", "{{len .}} greetings", "2 greetings", false},
+		{"unclosed action", "{{range .}", "", true},
+		{"unknown field", "{{range .}}{{.Naem}}{{end}}", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GreetList([]string{"Ada", ""}, tt.tmpl)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("GreetList(%q) = %q, want an error", tt.tmpl, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("GreetList(%q) = %q, %v, want %q", tt.tmpl, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatListEmpty(t *testing.T) {
+	var b bytes.Buffer
+	if err := FormatList(&b, nil, "html"); err != nil {
+		t.Fatal(err)
+	}
+	if b.String() != "<ul>\n</ul>\n" {
+		t.Errorf("FormatList(nil, html) = %q, want an empty list", b.String())
+	}
+	if got := ListFormats(); !slices.Equal(got, []string{"html", "markdown"}) {
+		t.Errorf("ListFormats() = %q", got)
+	}
+}
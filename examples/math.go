@@ -0,0 +1,141 @@
+package examples
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"slices"
+)
+
+// ErrOverflow is returned when the result of an operation does not fit in
+// its integer type
+var ErrOverflow = errors.New("integer overflow")
+
+// ErrDivideByZero is returned when dividing by zero
+var ErrDivideByZero = errors.New("division by zero")
+
+// ErrEmpty is returned by aggregate functions called with no values
+var ErrEmpty = errors.New("no values")
+
+// Add returns the sum of two integers
+func Add(a, b int) int {
+	return Sum(a, b)
+}
+
+// AddChecked returns the sum of two integers, or ErrOverflow if the sum
+// wraps around. Overflow can only happen when both operands have the same
+// sign, and shows up as a result whose sign differs from theirs.
+func AddChecked(a, b int) (int, error) {
+	sum := a + b
+	if (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0) {
+		return 0, ErrOverflow
+	}
+	return sum, nil
+}
+
+// Sum returns the total of nums, or 0 when called with no arguments
+func Sum(nums ...int) int {
+	total := 0
+	for _, n := range nums {
+		total += n
+	}
+	return total
+}
+
+// Subtract returns the difference of two integers
+func Subtract(a, b int) int {
+	return a - b
+}
+
+// SubtractChecked returns the difference of two integers, or ErrOverflow
+// if it wraps around: subtracting a negative number must increase a, and
+// anything else must not.
+func SubtractChecked(a, b int) (int, error) {
+	diff := a - b
+	if (b < 0) != (diff > a) {
+		return 0, ErrOverflow
+	}
+	return diff, nil
+}
+
+// Multiply returns the product of two integers
+func Multiply(a, b int) int {
+	return a * b
+}
+
+// MultiplyChecked returns the product of two integers, or ErrOverflow if
+// it wraps around, which shows up as a product that dividing by b does not
+// undo. math.MinInt times -1 is checked separately because that division
+// wraps too.
+func MultiplyChecked(a, b int) (int, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	product := a * b
+	if (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) || product/b != a {
+		return 0, ErrOverflow
+	}
+	return product, nil
+}
+
+// Divide returns the quotient of a divided by b, truncated toward zero.
+// It returns ErrDivideByZero instead of panicking when b is zero, and
+// ErrOverflow for math.MinInt divided by -1, whose quotient is one more
+// than math.MaxInt.
+func Divide(a, b int) (int, error) {
+	if b == 0 {
+		return 0, ErrDivideByZero
+	}
+	if a == math.MinInt && b == -1 {
+		return 0, ErrOverflow
+	}
+	return a / b, nil
+}
+
+// Min returns the smallest of nums, or ErrEmpty when called with no arguments
+func Min(nums ...int) (int, error) {
+	if len(nums) == 0 {
+		return 0, ErrEmpty
+	}
+	return slices.Min(nums), nil
+}
+
+// Max returns the largest of nums, or ErrEmpty when called with no arguments
+func Max(nums ...int) (int, error) {
+	if len(nums) == 0 {
+		return 0, ErrEmpty
+	}
+	return slices.Max(nums), nil
+}
+
+// Average returns the arithmetic mean of nums, or ErrEmpty when called with
+// no arguments. The values are summed into an int64, so the total can
+// exceed the range of int on 32-bit platforms; if it exceeds even int64,
+// as several values near math.MaxInt64 do, the sum is recomputed exactly
+// with math/big.
+func Average(nums ...int) (float64, error) {
+	if len(nums) == 0 {
+		return 0, ErrEmpty
+	}
+	var total int64
+	for _, n := range nums {
+		next := total + int64(n)
+		if (n >= 0) != (next >= total) {
+			return averageBig(nums), nil
+		}
+		total = next
+	}
+	return float64(total) / float64(len(nums)), nil
+}
+
+// averageBig is Average computed with arbitrary precision.
+func averageBig(nums []int) float64 {
+	total := new(big.Int)
+	for _, n := range nums {
+		total.Add(total, big.NewInt(int64(n)))
+	}
+	quo := new(big.Float).SetInt(total)
+	quo.Quo(quo, big.NewFloat(float64(len(nums))))
+	f, _ := quo.Float64()
+	return f
+}
@@ -0,0 +1,347 @@
+package examples
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestAdd(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     int
+		expected int
+	}{
+		{"positive numbers", 2, 3, 5},
+		{"with zero", 0, 5, 5},
+		{"negative numbers", -2, -3, -5},
+		{"mixed signs", -2, 3, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Add(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("Add(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAddChecked(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     int
+		expected int
+		err      error
+	}{
+		{"positive numbers", 2, 3, 5, nil},
+		{"mixed signs", -2, 3, 1, nil},
+		{"max plus zero", math.MaxInt, 0, math.MaxInt, nil},
+		{"max plus min", math.MaxInt, math.MinInt, -1, nil},
+		{"min plus zero", math.MinInt, 0, math.MinInt, nil},
+		{"max plus one", math.MaxInt, 1, 0, ErrOverflow},
+		{"one plus max", 1, math.MaxInt, 0, ErrOverflow},
+		{"min minus one", math.MinInt, -1, 0, ErrOverflow},
+		{"min plus min", math.MinInt, math.MinInt, 0, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := AddChecked(tt.a, tt.b)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("AddChecked(%d, %d) error = %v, want %v", tt.a, tt.b, err, tt.err)
+			}
+			if result != tt.expected {
+				t.Errorf("AddChecked(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSum(t *testing.T) {
+	large := make([]int, 10000)
+	for i := range large {
+		large[i] = i + 1
+	}
+
+	tests := []struct {
+		name     string
+		nums     []int
+		expected int
+	}{
+		{"no arguments", nil, 0},
+		{"single value", []int{7}, 7},
+		{"two values", []int{2, 3}, 5},
+		{"mixed signs", []int{-4, 10, -1}, 5},
+		{"large slice", large, 10000 * 10001 / 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Sum(tt.nums...)
+			if result != tt.expected {
+				t.Errorf("Sum(%d values) = %d, want %d", len(tt.nums), result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSumDoesNotAllocate(t *testing.T) {
+	nums := make([]int, 10000)
+	allocs := testing.AllocsPerRun(100, func() {
+		Sum(nums...)
+		Add(1, 2)
+	})
+	if allocs != 0 {
+		t.Errorf("Sum allocated %v times per run, want 0", allocs)
+	}
+}
+
+func BenchmarkSum(b *testing.B) {
+	nums := make([]int, 1000)
+	for i := range nums {
+		nums[i] = i
+	}
+	for b.Loop() {
+		Sum(nums...)
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     int
+		expected int
+	}{
+		{"positive result", 5, 3, 2},
+		{"negative result", 3, 5, -2},
+		{"minus negative", 3, -5, 8},
+		{"with zero", 0, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Subtract(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("Subtract(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSubtractChecked(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     int
+		expected int
+		err      error
+	}{
+		{"positive result", 5, 3, 2, nil},
+		{"minus negative", 3, -5, 8, nil},
+		{"minus zero", math.MinInt, 0, math.MinInt, nil},
+		{"minus one minus min", -1, math.MinInt, math.MaxInt, nil},
+		{"zero minus max", 0, math.MaxInt, -math.MaxInt, nil},
+		{"zero minus min", 0, math.MinInt, 0, ErrOverflow},
+		{"one minus min", 1, math.MinInt, 0, ErrOverflow},
+		{"min minus one", math.MinInt, 1, 0, ErrOverflow},
+		{"max minus minus one", math.MaxInt, -1, 0, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SubtractChecked(tt.a, tt.b)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SubtractChecked(%d, %d) error = %v, want %v", tt.a, tt.b, err, tt.err)
+			}
+			if result != tt.expected {
+				t.Errorf("SubtractChecked(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     int
+		expected int
+	}{
+		{"positive numbers", 4, 3, 12},
+		{"by zero", 4, 0, 0},
+		{"negative numbers", -4, -3, 12},
+		{"mixed signs", -4, 3, -12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Multiply(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("Multiply(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMultiplyChecked(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     int
+		expected int
+		err      error
+	}{
+		{"positive numbers", 4, 3, 12, nil},
+		{"by zero", math.MaxInt, 0, 0, nil},
+		{"zero by min", 0, math.MinInt, 0, nil},
+		{"negative numbers", -4, -3, 12, nil},
+		{"max by one", math.MaxInt, 1, math.MaxInt, nil},
+		{"max by minus one", math.MaxInt, -1, -math.MaxInt, nil},
+		{"min by one", math.MinInt, 1, math.MinInt, nil},
+		{"half min by two", math.MinInt / 2, 2, math.MinInt, nil},
+		{"max by two", math.MaxInt, 2, 0, ErrOverflow},
+		{"min by minus one", math.MinInt, -1, 0, ErrOverflow},
+		{"minus one by min", -1, math.MinInt, 0, ErrOverflow},
+		{"min by min", math.MinInt, math.MinInt, 0, ErrOverflow},
+		{"large factors", 1 << 32, 1 << 32, 0, ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MultiplyChecked(tt.a, tt.b)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("MultiplyChecked(%d, %d) error = %v, want %v", tt.a, tt.b, err, tt.err)
+			}
+			if result != tt.expected {
+				t.Errorf("MultiplyChecked(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDivide(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     int
+		expected int
+		err      error
+	}{
+		{"exact", 12, 3, 4, nil},
+		{"truncates", 7, 2, 3, nil},
+		{"truncates toward zero", -7, 2, -3, nil},
+		{"zero dividend", 0, 5, 0, nil},
+		{"by zero", 1, 0, 0, ErrDivideByZero},
+		{"zero by zero", 0, 0, 0, ErrDivideByZero},
+		{"min by minus one", math.MinInt, -1, 0, ErrOverflow},
+		{"min by one", math.MinInt, 1, math.MinInt, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Divide(tt.a, tt.b)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Divide(%d, %d) error = %v, want %v", tt.a, tt.b, err, tt.err)
+			}
+			if result != tt.expected {
+				t.Errorf("Divide(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	tests := []struct {
+		name    string
+		nums    []int
+		wantMin int
+		wantMax int
+	}{
+		{"single value", []int{7}, 7, 7},
+		{"ascending", []int{1, 2, 3}, 1, 3},
+		{"mixed signs", []int{-4, 10, -1}, -4, 10},
+		{"duplicates", []int{5, 5, 5}, 5, 5},
+		{"extremes", []int{math.MaxInt, math.MinInt}, math.MinInt, math.MaxInt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Min(tt.nums...); err != nil || got != tt.wantMin {
+				t.Errorf("Min(%v) = %d, %v, want %d, nil", tt.nums, got, err, tt.wantMin)
+			}
+			if got, err := Max(tt.nums...); err != nil || got != tt.wantMax {
+				t.Errorf("Max(%v) = %d, %v, want %d, nil", tt.nums, got, err, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestAverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		nums     []int
+		expected float64
+	}{
+		{"single value", []int{7}, 7},
+		{"whole mean", []int{2, 4, 6}, 4},
+		{"fractional mean is not truncated", []int{1, 2}, 1.5},
+		{"negative fractional mean", []int{-1, -2}, -1.5},
+		{"mixed signs", []int{-4, 10, -1}, 5.0 / 3},
+		// A plain int sum of these wraps around and yields a negative mean.
+		{"sum exceeds int", []int{math.MaxInt, math.MaxInt, math.MaxInt}, float64(math.MaxInt)},
+		{"sum below min int", []int{math.MinInt, math.MinInt}, float64(math.MinInt)},
+		{"overflow then back in range", []int{math.MaxInt, 1, -2}, float64(math.MaxInt-1) / 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Average(tt.nums...)
+			if err != nil {
+				t.Fatalf("Average(%v) error = %v", tt.nums, err)
+			}
+			if got != tt.expected {
+				t.Errorf("Average(%v) = %v, want %v", tt.nums, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAggregatesRejectEmptyInput(t *testing.T) {
+	if _, err := Min(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Min() error = %v, want %v", err, ErrEmpty)
+	}
+	if _, err := Max(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Max() error = %v, want %v", err, ErrEmpty)
+	}
+	if _, err := Average(); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Average() error = %v, want %v", err, ErrEmpty)
+	}
+}
+
+func FuzzAddChecked(f *testing.F) {
+	seeds := [][2]int{
+		{0, 0}, {1, -1}, {2, 3},
+		{math.MaxInt, 0}, {math.MaxInt, 1}, {math.MaxInt, math.MaxInt},
+		{math.MinInt, 0}, {math.MinInt, -1}, {math.MinInt, math.MinInt},
+		{math.MaxInt, math.MinInt},
+	}
+	for _, s := range seeds {
+		f.Add(s[0], s[1])
+	}
+	minInt, maxInt := big.NewInt(math.MinInt), big.NewInt(math.MaxInt)
+	f.Fuzz(func(t *testing.T, a, b int) {
+		want := new(big.Int).Add(big.NewInt(int64(a)), big.NewInt(int64(b)))
+		fits := want.Cmp(minInt) >= 0 && want.Cmp(maxInt) <= 0
+
+		result, err := AddChecked(a, b)
+		switch {
+		case err == nil && !fits:
+			t.Fatalf("AddChecked(%d, %d) = %d, want ErrOverflow (true sum %s)", a, b, result, want)
+		case err != nil && fits:
+			t.Fatalf("AddChecked(%d, %d) error = %v, want %s", a, b, err, want)
+		case err != nil && !errors.Is(err, ErrOverflow):
+			t.Fatalf("AddChecked(%d, %d) error = %v, want ErrOverflow", a, b, err)
+		case err == nil && big.NewInt(int64(result)).Cmp(want) != 0:
+			t.Fatalf("AddChecked(%d, %d) = %d, want %s", a, b, result, want)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package examples
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// GreetProvider produces a greeting for a name. Providers registered with
+// RegisterProvider can be selected by name, for example with greet
+// --provider.
+type GreetProvider interface {
+	Greet(name string) string
+}
+
+// GreetProviderFunc adapts an ordinary function to a GreetProvider.
+type GreetProviderFunc func(name string) string
+
+// Greet calls f(name).
+func (f GreetProviderFunc) Greet(name string) string { return f(name) }
+
+// DefaultProvider is the name of the built-in provider, which greets by
+// time of day like Greet.
+const DefaultProvider = "default"
+
+var providers = struct {
+	sync.RWMutex
+	m map[string]GreetProvider
+}{m: make(map[string]GreetProvider)}
+
+func init() {
+	RegisterProvider(DefaultProvider, GreetProviderFunc(Greet))
+	RegisterProvider("formal", GreetProviderFunc(func(name string) string {
+		return fmt.Sprintf("Good day to you, %s.", nameOrWorld(name))
+	}))
+	RegisterProvider("casual", GreetProviderFunc(func(name string) string {
+		return "yo " + nameOrWorld(name)
+	}))
+}
+
+// RegisterProvider makes p available under name. It is meant to be called
+// from init functions and panics if name is empty, p is nil or name is
+// already registered, so mistakes surface as soon as the program starts.
+// It is safe to call concurrently with LookupProvider.
+func RegisterProvider(name string, p GreetProvider) {
+	if name == "" {
+		panic("examples: RegisterProvider with empty name")
+	}
+	if p == nil {
+		panic("examples: RegisterProvider " + name + " with nil provider")
+	}
+	providers.Lock()
+	defer providers.Unlock()
+	if _, dup := providers.m[name]; dup {
+		panic("examples: RegisterProvider called twice for " + name)
+	}
+	providers.m[name] = p
+}
+
+// LookupProvider returns the provider registered under name.
+func LookupProvider(name string) (GreetProvider, bool) {
+	providers.RLock()
+	defer providers.RUnlock()
+	p, ok := providers.m[name]
+	return p, ok
+}
+
+// ProviderNames returns the names of the registered providers, sorted.
+func ProviderNames() []string {
+	providers.RLock()
+	defer providers.RUnlock()
+	return slices.Sorted(maps.Keys(providers.m))
+}
+
+// nameOrWorld cleans name with SanitizeName, falling back to "World".
+func nameOrWorld(name string) string {
+	if name = SanitizeName(name); name == "" {
+		return "World"
+	}
+	return name
+}
@@ -0,0 +1,94 @@
+package examples
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestProviders(t *testing.T) {
+	setNow(t, time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		provider string
+		input    string
+		expected string
+	}{
+		{"default", "Alice", "Hello, Alice!"},
+		{"formal", "Alice", "Good day to you, Alice."},
+		{"formal", "", "Good day to you, World."},
+		{"casual", "Alice", "yo Alice"},
+		{"casual", " Bob\x1b ", "yo Bob"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+"/"+tt.input, func(t *testing.T) {
+			p, ok := LookupProvider(tt.provider)
+			if !ok {
+				t.Fatalf("LookupProvider(%q) not found", tt.provider)
+			}
+			if result := p.Greet(tt.input); result != tt.expected {
+				t.Errorf("%s.Greet(%q) = %q, want %q", tt.provider, tt.input, result, tt.expected)
+			}
+		})
+	}
+
+	if _, ok := LookupProvider("nope"); ok {
+		t.Error(`LookupProvider("nope") found a provider`)
+	}
+	if names := ProviderNames(); !slices.IsSorted(names) || !slices.Contains(names, "formal") {
+		t.Errorf("ProviderNames() = %q, want a sorted list including formal", names)
+	}
+}
+
+func TestRegisterProviderPanics(t *testing.T) {
+	p := GreetProviderFunc(func(string) string { return "" })
+	tests := []struct {
+		name     string
+		register func()
+		want     string
+	}{
+		{"duplicate", func() { RegisterProvider("formal", p) }, "called twice for formal"},
+		{"empty name", func() { RegisterProvider("", p) }, "empty name"},
+		{"nil provider", func() { RegisterProvider("test-nil", nil) }, "nil provider"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if msg, _ := r.(string); !strings.Contains(msg, tt.want) {
+					t.Errorf("panic = %v, want it to mention %q", r, tt.want)
+				}
+			}()
+			tt.register()
+		})
+	}
+}
+
+func TestRegisterProviderConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		name := fmt.Sprintf("test-concurrent-%d", i)
+		go func() {
+			defer wg.Done()
+			RegisterProvider(name, GreetProviderFunc(func(n string) string { return name + " " + n }))
+		}()
+		go func() {
+			defer wg.Done()
+			LookupProvider("formal")
+			ProviderNames()
+		}()
+	}
+	wg.Wait()
+	for i := range 8 {
+		name := fmt.Sprintf("test-concurrent-%d", i)
+		if p, ok := LookupProvider(name); !ok || p.Greet("x") != name+" x" {
+			t.Errorf("provider %s not registered", name)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package examples
+
+import (
+	"fmt"
+	"math/rand/v2"
+)
+
+// randomSalutations are the salutations GreetRandom chooses from
+var randomSalutations = []string{"Hello", "Hi", "Hey", "Greetings"}
+
+// GreetRandom returns a greeting for name with a salutation chosen by r, so
+// a seeded r always yields the same sequence of greetings. The name is
+// cleaned with SanitizeName and defaults to "World". A nil r uses the
+// package-level random source.
+func GreetRandom(name string, r *rand.Rand) string {
+	name = SanitizeName(name)
+	if name == "" {
+		name = "World"
+	}
+	var i int
+	if r == nil {
+		i = rand.IntN(len(randomSalutations))
+	} else {
+		i = r.IntN(len(randomSalutations))
+	}
+	return fmt.Sprintf("%s, %s!", randomSalutations[i], name)
+}
@@ -0,0 +1,69 @@
+package examples
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGreetRandomSequence(t *testing.T) {
+	r := rand.New(rand.NewPCG(42, 0))
+	var got []string
+	for range 8 {
+		got = append(got, GreetRandom("Ada", r))
+	}
+	want := []string{
+		"Greetings, Ada!",
+		"Hello, Ada!",
+		"Greetings, Ada!",
+		"Hi, Ada!",
+		"Hi, Ada!",
+		"Hi, Ada!",
+		"Hello, Ada!",
+		"Hey, Ada!",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("GreetRandom sequence with seed 42 =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestGreetRandom(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // suffix after the salutation
+	}{
+		{"plain", "Alice", ", Alice!"},
+		{"empty name", "", ", World!"},
+		{"sanitized", " Bob\x1b ", ", Bob!"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GreetRandom(tt.input, rand.New(rand.NewPCG(1, 2)))
+			salutation, ok := strings.CutSuffix(got, tt.want)
+			if !ok || !slices.Contains(randomSalutations, salutation) {
+				t.Errorf("GreetRandom(%q) = %q, want a known salutation followed by %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetRandomUsesEverySalutation(t *testing.T) {
+	r := rand.New(rand.NewPCG(7, 7))
+	seen := map[string]bool{}
+	for range 200 {
+		salutation, _, _ := strings.Cut(GreetRandom("x", r), ",")
+		seen[salutation] = true
+	}
+	for _, s := range randomSalutations {
+		if !seen[s] {
+			t.Errorf("salutation %q never chosen in 200 greetings", s)
+		}
+	}
+	// A nil source falls back to the global one.
+	if got := GreetRandom("x", nil); !strings.HasSuffix(got, ", x!") {
+		t.Errorf("GreetRandom(x, nil) = %q", got)
+	}
+}
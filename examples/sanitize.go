@@ -0,0 +1,79 @@
+package examples
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxNameLength is the longest name, in characters, that SanitizeName keeps
+// before truncating. A character is a rune together with any combining
+// marks that follow it, so accents never count against the limit
+var MaxNameLength = 256
+
+// ellipsis marks a name that SanitizeName shortened
+const ellipsis = "…"
+
+// SanitizeName makes an untrusted name safe to print to a terminal. It
+// removes control characters (including newlines and the ESC that starts
+// ANSI escape sequences) and bytes that are not valid UTF-8, normalizes the
+// result to NFC so composed and decomposed spellings of a name are
+// identical, trims leading and trailing Unicode white space, and truncates
+// it to MaxNameLength characters, ending it with an ellipsis if it was
+// shortened.
+func SanitizeName(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i, r := range s {
+		if r == utf8.RuneError {
+			if _, size := utf8.DecodeRuneInString(s[i:]); size <= 1 {
+				continue
+			}
+		}
+		if unicode.IsControl(r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	name := norm.NFC.String(b.String())
+	return truncate(strings.TrimFunc(name, unicode.IsSpace), MaxNameLength)
+}
+
+// truncate shortens s to at most max characters, replacing the tail with
+// an ellipsis when anything is cut. A character is never separated from
+// its combining marks.
+func truncate(s string, max int) string {
+	if max <= 0 || charCount(s) <= max {
+		return s
+	}
+	keep := max - utf8.RuneCountInString(ellipsis)
+	end, n := 0, 0
+	for i, r := range s {
+		if i == 0 || !isCombining(r) {
+			if n == keep {
+				break
+			}
+			n++
+		}
+		end = i + utf8.RuneLen(r)
+	}
+	return s[:end] + ellipsis
+}
+
+// charCount returns the number of characters in s, counting combining
+// marks as part of the character before them.
+func charCount(s string) int {
+	n := 0
+	for i, r := range s {
+		if i == 0 || !isCombining(r) {
+			n++
+		}
+	}
+	return n
+}
+
+func isCombining(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc)
+}
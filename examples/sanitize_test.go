@@ -0,0 +1,105 @@
+package examples
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestSanitizeName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain", "Alice", "Alice"},
+		{"empty", "", ""},
+		{"keeps spaces and unicode", "José María 東京", "José María 東京"},
+		{"strips newline and tab", "Bob\n\tSmith", "BobSmith"},
+		{"strips escape byte", "Bob\x1b[31mX", "Bob[31mX"},
+		{"strips NUL and DEL", "a\x00b\x7fc", "abc"},
+		{"strips C1 control", "a\u009b31mb", "a31mb"},
+		{"strips invalid UTF-8", "Al\xffice\xfe", "Alice"},
+		{"keeps literal replacement char", "a�b", "a�b"},
+		{"only controls", "\r\n\x1b", ""},
+		{"composes decomposed accent", "Jose\u0301", "Jos\u00e9"},
+		{"keeps composed accent", "Jos\u00e9", "Jos\u00e9"},
+		{"trims ASCII space", "  Alice\t", "Alice"},
+		{"trims Unicode spaces", "\u00a0\u3000Alice\u2003\u202f", "Alice"},
+		{"keeps inner Unicode space", "Ana\u00a0María", "Ana\u00a0María"},
+		{"only spaces", " \u2002 ", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SanitizeName(tt.input)
+			if result != tt.expected {
+				t.Errorf("SanitizeName(%q) = %q, want %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSanitizeNameTruncates(t *testing.T) {
+	tests := []struct {
+		name     string
+		max      int
+		input    string
+		expected string
+	}{
+		{"at limit", 5, "Alice", "Alice"},
+		{"over limit", 5, "Alexander", "Alex…"},
+		{"multibyte runes", 3, "東京都庁", "東京…"},
+		{"limit of one", 1, "Bob", "…"},
+		{"disabled", 0, "Alexander", "Alexander"},
+		{"accent is not counted", 5, "Jose\u0301!", "Jos\u00e9!"},
+		// U+0331 has no precomposed form with o, so it stays a separate
+		// rune after NFC, yet the pair counts as one character.
+		{"uncomposable mark is not counted", 3, "o\u0331o\u0331", "o\u0331o\u0331"},
+		{"mark is kept with its base", 3, "o\u0331o\u0331o\u0331o\u0331", "o\u0331o\u0331…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := MaxNameLength
+			MaxNameLength = tt.max
+			t.Cleanup(func() { MaxNameLength = orig })
+
+			result := SanitizeName(tt.input)
+			if result != tt.expected {
+				t.Errorf("SanitizeName(%q) with max %d = %q, want %q", tt.input, tt.max, result, tt.expected)
+			}
+		})
+	}
+
+	long := SanitizeName(strings.Repeat("x", 1000))
+	if n := utf8.RuneCountInString(long); n != 256 {
+		t.Errorf("default limit kept %d runes, want 256", n)
+	}
+}
+
+func TestGreetNormalizesName(t *testing.T) {
+	setNow(t, time.Date(2025, 1, 1, 23, 0, 0, 0, time.UTC))
+	composed := Greet("Jos\u00e9")
+	decomposed := Greet("Jose\u0301")
+	if composed != decomposed {
+		t.Errorf("Greet(composed) = %q, Greet(decomposed) = %q, want them equal", composed, decomposed)
+	}
+	if composed != "Hello, Jos\u00e9!" {
+		t.Errorf("Greet(%q) = %q, want %q", "Jos\u00e9", composed, "Hello, Jos\u00e9!")
+	}
+	if got := Greet("\u3000 "); got != "Hello, World!" {
+		t.Errorf("Greet(spaces) = %q, want %q", got, "Hello, World!")
+	}
+}
+
+func TestGreetStripsEscapes(t *testing.T) {
+	result := Greet("Bob\n\033[31mX")
+	if strings.ContainsAny(result, "\x1b\n") {
+		t.Errorf("Greet returned raw control bytes: %q", result)
+	}
+	if !strings.HasSuffix(result, ", Bob[31mX!") {
+		t.Errorf("Greet = %q, want sanitized name", result)
+	}
+}
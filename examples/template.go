@@ -0,0 +1,129 @@
+package examples
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// TemplateData is the value a greeting template is executed with
+type TemplateData struct {
+	Name string    // sanitized name, "World" if empty
+	Time time.Time // when the greeting is rendered
+}
+
+// ErrUnknownField is returned by GreetTemplate for a template that refers
+// to a field TemplateData does not have, such as {{.Naem}}
+var ErrUnknownField = errors.New("unknown template field")
+
+// GreetTemplate renders a greeting for name using tmpl, a text/template
+// such as "Hey {{.Name}}, welcome!". The template receives a TemplateData.
+// An error is returned if tmpl cannot be parsed or executed. References to
+// fields TemplateData lacks are caught before execution and reported with
+// ErrUnknownField, naming the field, instead of the executor's less direct
+// message; missing map keys are errors too rather than "<no value>".
+func GreetTemplate(name, tmpl string) (string, error) {
+	t, err := template.New("greeting").Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+	if err := checkFields(t.Root, true); err != nil {
+		return "", err
+	}
+	name = SanitizeName(name)
+	if name == "" {
+		name = "World"
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, TemplateData{Name: name, Time: now()}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// checkFields reports the first field reference in n, such as .Naem or
+// $.Naem, that is not a field or method of TemplateData. dotIsData says
+// whether dot is the TemplateData; inside the body of a range or with it
+// is something else, so only $ references are checked there.
+func checkFields(n parse.Node, dotIsData bool) error {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Nodes {
+			if err := checkFields(c, dotIsData); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkFields(n.Pipe, dotIsData)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, c := range n.Cmds {
+			if err := checkFields(c, dotIsData); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if err := checkFields(arg, dotIsData); err != nil {
+				return err
+			}
+		}
+	case *parse.IfNode:
+		return walkBranch(&n.BranchNode, dotIsData, dotIsData)
+	case *parse.RangeNode:
+		return walkBranch(&n.BranchNode, dotIsData, false)
+	case *parse.WithNode:
+		return walkBranch(&n.BranchNode, dotIsData, false)
+	case *parse.FieldNode:
+		if dotIsData {
+			return checkField(n.Ident[0])
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			return checkField(n.Ident[1])
+		}
+	}
+	return nil
+}
+
+// walkBranch checks an if, range or with: its pipeline and else branch
+// see the enclosing dot, its body sees dot as bodyDotIsData says.
+func walkBranch(n *parse.BranchNode, dotIsData, bodyDotIsData bool) error {
+	if err := checkFields(n.Pipe, dotIsData); err != nil {
+		return err
+	}
+	if err := checkFields(n.List, bodyDotIsData); err != nil {
+		return err
+	}
+	return checkFields(n.ElseList, dotIsData)
+}
+
+var templateDataType = reflect.TypeFor[TemplateData]()
+
+func checkField(name string) error {
+	if _, ok := templateDataType.FieldByName(name); ok {
+		return nil
+	}
+	if _, ok := templateDataType.MethodByName(name); ok {
+		return nil
+	}
+	return fmt.Errorf("%w %q: want one of %s", ErrUnknownField, name, strings.Join(templateFields(), ", "))
+}
+
+// templateFields returns the names of TemplateData's fields, in order.
+func templateFields() []string {
+	names := make([]string, templateDataType.NumField())
+	for i := range names {
+		names[i] = templateDataType.Field(i).Name
+	}
+	return names
+}
@@ -0,0 +1,57 @@
+package examples
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGreetTemplate(t *testing.T) {
+	setNow(t, time.Date(2025, 3, 14, 15, 9, 0, 0, time.UTC))
+
+	tests := []struct {
+		name     string
+		input    string
+		tmpl     string
+		expected string
+		wantErr  bool
+	}{
+		{"name", "Alice", "Hey {{.Name}}, welcome!", "Hey Alice, welcome!", false},
+		{"empty name", "", "Hey {{.Name}}!", "Hey World!", false},
+		{"sanitizes name", "Bob\x1b[2J", "<{{.Name}}>", "<Bob[2J>", false},
+		{"time", "Alice", "{{.Name}} at {{.Time.Format \"15:04\"}}", "Alice at 15:09", false},
+		{"static text", "Alice", "Hi there", "Hi there", false},
+		{"parse error", "Alice", "Hey {{.Name", "", true},
+		{"exec error", "Alice", "{{.Name.Nope}}", "", true},
+		{"unknown field", "Alice", "Hi {{.Naem}}", "", true},
+		{"unknown root field", "Alice", "{{with .Time}}{{$.Naem}}{{end}}", "", true},
+		{"unknown field in else", "Alice", "{{with .Name}}{{.}}{{else}}{{.Naem}}{{end}}", "", true},
+		{"field in if", "Alice", "{{if .Name}}Hi {{.Name}}{{end}}", "Hi Alice", false},
+		{"dot rebound by with", "Alice", "{{with .Time}}{{.Year}}{{end}}", "2025", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GreetTemplate(tt.input, tt.tmpl)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GreetTemplate(%q, %q) error = %v, wantErr %v", tt.input, tt.tmpl, err, tt.wantErr)
+			}
+			if result != tt.expected {
+				t.Errorf("GreetTemplate(%q, %q) = %q, want %q", tt.input, tt.tmpl, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGreetTemplateUnknownField(t *testing.T) {
+	_, err := GreetTemplate("Alice", "Hello, {{.Naem}}!")
+	if !errors.Is(err, ErrUnknownField) {
+		t.Fatalf("GreetTemplate() error = %v, want ErrUnknownField", err)
+	}
+	for _, want := range []string{`"Naem"`, "Name, Time"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+}
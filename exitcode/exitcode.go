@@ -0,0 +1,37 @@
+// Package exitcode carries the exit status of a failed tool invocation up
+// to the process that ran it, so a wrapper can exit with the same code.
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ExitError reports that a tool ran but exited with a non-zero status.
+type ExitError struct {
+	Tool   string // name of the tool, such as "claude"
+	Code   int    // the tool's exit status
+	Stderr string // its standard error output, trimmed; may be empty
+	Err    error  // the underlying error, usually an *exec.ExitError
+}
+
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("%s: exit status %d", e.Tool, e.Code)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	return msg
+}
+
+func (e *ExitError) Unwrap() error { return e.Err }
+
+// From returns the exit status carried by an *ExitError in err's chain.
+// It reports false if there is none or its code is not a valid exit
+// status (1 to 255).
+func From(err error) (int, bool) {
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code < 1 || exitErr.Code > 255 {
+		return 0, false
+	}
+	return exitErr.Code, true
+}
@@ -0,0 +1,55 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExitError(t *testing.T) {
+	base := errors.New("exit status 3")
+	tests := []struct {
+		name     string
+		err      *ExitError
+		wantText string
+	}{
+		{"with stderr", &ExitError{Tool: "claude", Code: 3, Stderr: "rate limited", Err: base}, "claude: exit status 3: rate limited"},
+		{"without stderr", &ExitError{Tool: "opencode", Code: 42, Err: base}, "opencode: exit status 42"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.wantText {
+				t.Errorf("Error() = %q, want %q", got, tt.wantText)
+			}
+			if !errors.Is(tt.err, base) {
+				t.Error("ExitError does not unwrap to the underlying error")
+			}
+		})
+	}
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		want   int
+		wantOK bool
+	}{
+		{"nil", nil, 0, false},
+		{"plain error", errors.New("boom"), 0, false},
+		{"exit error", &ExitError{Tool: "claude", Code: 42}, 42, true},
+		{"wrapped", fmt.Errorf("ask: %w", &ExitError{Tool: "claude", Code: 7}), 7, true},
+		{"killed by signal", &ExitError{Tool: "claude", Code: -1}, 0, false},
+		{"out of range", &ExitError{Tool: "claude", Code: 300}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := From(tt.err)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("From(%v) = %d, %v, want %d, %v", tt.err, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ericbfriday/claude-go-containers/examples"
+)
+
+func fibCommand() command {
+	return command{
+		name:    "fib",
+		args:    "[--big] <n>",
+		summary: "Print the nth Fibonacci number",
+		setFlags: func(fs *flag.FlagSet) handler {
+			useBig := fs.Bool("big", false, fmt.Sprintf("use arbitrary precision, allowing n > %d", examples.MaxFibonacci))
+			return func(a *app, args []string) (Output, error) {
+				if len(args) != 1 {
+					return nil, usagef("expected exactly one argument, got %d", len(args))
+				}
+				n, err := parseOperand(args[0])
+				if err != nil {
+					return nil, err
+				}
+				if n < 0 {
+					return nil, usagef("n must not be negative, got %d", n)
+				}
+				if *useBig {
+					f, err := examples.FibonacciBig(n)
+					if err != nil {
+						return nil, err
+					}
+					return fibResult{N: n, Result: json.Number(f.String())}, nil
+				}
+				f, err := examples.Fibonacci(n)
+				if errors.Is(err, examples.ErrOverflow) {
+					return nil, fmt.Errorf("%w: fib %d does not fit in 64 bits; use --big", err, n)
+				}
+				if err != nil {
+					return nil, err
+				}
+				return fibResult{N: n, Result: json.Number(strconv.FormatUint(f, 10))}, nil
+			}
+		},
+	}
+}
+
+// fibResult is a Fibonacci number. Result is kept as a number literal so
+// values beyond a uint64 encode exactly in JSON.
+type fibResult struct {
+	N      int         `json:"n"`
+	Result json.Number `json:"result"`
+}
+
+func (r fibResult) Text(w io.Writer) error {
+	_, err := fmt.Fprintln(w, r.Result)
+	return err
+}
+
+func (r fibResult) JSON(w io.Writer) error { return writeJSON(w, r) }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestFibCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{"text", []string{"fib", "10"}, exitOK, "55\n", ""},
+		{"largest uint64", []string{"fib", "93"}, exitOK, "12200160415121876738\n", ""},
+		{"overflow", []string{"fib", "94"}, exitError, "", "integer overflow: fib 94 does not fit in 64 bits; use --big"},
+		{"big", []string{"fib", "--big", "100"}, exitOK, "354224848179261915075\n", ""},
+		{"json", []string{"--json", "fib", "--big", "100"}, exitOK, `{"n":100,"result":354224848179261915075}` + "\n", ""},
+		{"negative", []string{"fib", "--", "-1"}, exitUsage, "", "n must not be negative"},
+		{"invalid", []string{"fib", "x"}, exitUsage, "", `invalid integer "x"`},
+		{"no argument", []string{"fib"}, exitUsage, "", "expected exactly one argument"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := run(tt.args, &out, &errOut)
+			if code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, errOut.String())
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErrOut)
+			}
+		})
+	}
+}
@@ -1,3 +1,13 @@
 module github.com/ericbfriday/claude-go-containers
 
 go 1.24.9
+
+require (
+	github.com/fsnotify/fsnotify v1.9.0
+	golang.org/x/sync v0.17.0
+	golang.org/x/text v0.30.0
+	golang.org/x/time v0.14.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.13.0 // indirect
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/color"
+	"github.com/ericbfriday/claude-go-containers/examples"
+	"github.com/ericbfriday/claude-go-containers/history"
+)
+
+// greeter produces the greeting for a single name.
+type greeter func(name string) (string, error)
+
+func greetCommand() command {
+	return command{
+		name:    "greet",
+		args:    "[--name NAME | --stdin | --name-file FILE] [--expand-env] [--lang LANG | --random [--seed N] | --provider NAME] [--names-format FORMAT] [--record]",
+		summary: "Print a greeting",
+		setFlags: func(fs *flag.FlagSet) handler {
+			name := fs.String("name", "", "name to greet (default \"World\")")
+			stdin := fs.Bool("stdin", false, "read newline-delimited names from standard input")
+			nameFile := fs.String("name-file", "", "read newline-delimited names from `file`, skipping lines starting with #")
+			lang := fs.String("lang", "", "greet in this language (en, es, fr, de) instead of by time of day")
+			random := fs.Bool("random", false, "pick a random salutation instead of greeting by time of day")
+			seed := fs.Uint64("seed", 0, "seed for --random, for repeatable output (default: seeded from the clock)")
+			provider := fs.String("provider", "", "greet with this registered provider: "+strings.Join(examples.ProviderNames(), ", "))
+			expandEnv := fs.Bool("expand-env", false, "expand $VAR and ${VAR} in names; undefined variables expand to nothing")
+			record := fs.Bool("record", false, "add each greeting to the history shown by the history command")
+			namesFormat := fs.String("names-format", "", "render all greetings together as "+strings.Join(examples.ListFormats(), " or ")+", or through this list template")
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 {
+					return nil, usagef("unexpected arguments: %v", args)
+				}
+				if moreThanOne(*lang != "", *random, *provider != "") {
+					return nil, usagef("--lang, --random and --provider are mutually exclusive")
+				}
+				greet := a.greeter(*lang)
+				switch {
+				case *random:
+					greet = randomGreeter(*seed)
+				case *provider != "":
+					p, ok := examples.LookupProvider(*provider)
+					if !ok {
+						return nil, usagef("unknown provider %q: want one of %s", *provider, strings.Join(examples.ProviderNames(), ", "))
+					}
+					greet = func(name string) (string, error) { return p.Greet(name), nil }
+				}
+				if *record {
+					store, err := a.historyStore()
+					if err != nil {
+						return nil, err
+					}
+					greet = recordingGreeter(greet, store)
+				}
+				greet = a.countingGreeter(greet)
+				// Names are expanded before anything else sees them, so the
+				// output, list and history all show the name that was greeted.
+				var expand func(string) string
+				if *expandEnv {
+					expand = func(name string) string { return os.Expand(name, a.getenv) }
+				}
+				if *stdin && *nameFile != "" {
+					return nil, usagef("--stdin and --name-file are mutually exclusive")
+				}
+				if !*stdin && *nameFile == "" {
+					if *namesFormat != "" {
+						return nil, usagef("--names-format requires --stdin or --name-file")
+					}
+					return newGreeting(*name, expand, greet, a.color)
+				}
+				if *name != "" {
+					return nil, usagef("--name cannot be combined with --stdin or --name-file")
+				}
+				stream := greetingStream{r: a.in, expand: expand, greet: greet, color: a.color}
+				if *nameFile != "" {
+					stream = greetingStream{path: *nameFile, comments: true, expand: expand, greet: greet, color: a.color}
+				}
+				if *namesFormat != "" {
+					if a.json {
+						return nil, usagef("--names-format cannot be combined with --json")
+					}
+					return greetingList{stream: stream, format: *namesFormat}, nil
+				}
+				return stream, nil
+			}
+		},
+	}
+}
+
+// greeter picks how names are greeted: in lang if one is given, otherwise
+// with the configured greeting template, otherwise by time of day.
+func (a *app) greeter(lang string) greeter {
+	switch {
+	case lang != "":
+		return func(name string) (string, error) { return examples.GreetLocalized(name, lang), nil }
+	case a.config.GreetingTemplate != "":
+		tmpl := a.config.GreetingTemplate
+		return func(name string) (string, error) { return examples.GreetTemplate(name, tmpl) }
+	default:
+		return func(name string) (string, error) { return examples.Greet(name), nil }
+	}
+}
+
+// moreThanOne reports whether more than one of set is true.
+func moreThanOne(set ...bool) bool {
+	n := 0
+	for _, s := range set {
+		if s {
+			n++
+		}
+	}
+	return n > 1
+}
+
+// randomGreeter returns a greeter choosing salutations at random from a
+// source seeded with seed, or with the current time if seed is 0.
+func randomGreeter(seed uint64) greeter {
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+	r := rand.New(rand.NewPCG(seed, 0))
+	return func(name string) (string, error) { return examples.GreetRandom(name, r), nil }
+}
+
+// countingGreeter returns a greeter that counts each successful greeting
+// in the app's metrics.
+func (a *app) countingGreeter(greet greeter) greeter {
+	return func(name string) (string, error) {
+		g, err := greet(name)
+		if err == nil {
+			a.metrics.IncGreetings(1)
+		}
+		return g, err
+	}
+}
+
+// recordingGreeter returns a greeter that records each successful
+// greeting in store, under the name as displayed by greetedName. Failing
+// to record it fails the greeting.
+func recordingGreeter(greet greeter, store history.Store) greeter {
+	return func(name string) (string, error) {
+		g, err := greet(name)
+		if err != nil {
+			return "", err
+		}
+		if err := store.Record(history.Entry{Name: greetedName(name), Greeting: g, Time: time.Now()}); err != nil {
+			return "", err
+		}
+		return g, nil
+	}
+}
+
+// greetedName returns name as a greeting shows it: sanitized with
+// examples.SanitizeName, and "World" if nothing is left.
+func greetedName(name string) string {
+	if name = examples.SanitizeName(name); name == "" {
+		return "World"
+	}
+	return name
+}
+
+// scanLines calls fn for each line read from r, stopping at the first
+// error fn returns. Lines of any length are supported; the scanner's buffer
+// grows as needed.
+func scanLines(r io.Reader, fn func(line string) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), math.MaxInt)
+	for sc.Scan() {
+		if err := fn(sc.Text()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
+
+// greeting is the result of greeting a single name. Name is the name
+// after any --expand-env expansion.
+type greeting struct {
+	Name     string `json:"name"`
+	Greeting string `json:"greeting"`
+
+	color color.Colorizer
+}
+
+// newGreeting greets name, first passing it through expand if that is
+// set. greet sanitizes the result, so control characters in expanded
+// variable values are stripped like any others.
+func newGreeting(name string, expand func(string) string, greet greeter, c color.Colorizer) (greeting, error) {
+	if expand != nil {
+		name = expand(name)
+	}
+	g, err := greet(name)
+	if err != nil {
+		return greeting{}, err
+	}
+	return greeting{Name: name, Greeting: g, color: c}, nil
+}
+
+func (g greeting) Text(w io.Writer) error {
+	_, err := fmt.Fprintln(w, g.color.Cyan(g.Greeting))
+	return err
+}
+
+func (g greeting) JSON(w io.Writer) error { return writeJSON(w, g) }
+
+// greetingStream greets each line read from r, or from the file at path
+// if one is set, as it is rendered, so large inputs are never held in
+// memory. Lines are trimmed of surrounding whitespace; blank lines get the
+// default greeting.
+type greetingStream struct {
+	r        io.Reader
+	path     string
+	comments bool                // skip lines starting with #
+	expand   func(string) string // applied to each name, if set
+	greet    greeter
+	color    color.Colorizer
+}
+
+// greetings calls fn with the greeting for each name in the stream.
+func (s greetingStream) greetings(fn func(greeting) error) error {
+	r := s.r
+	if s.path != "" {
+		f, err := os.Open(s.path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	return scanLines(r, func(line string) error {
+		name := strings.TrimSpace(line)
+		if s.comments && strings.HasPrefix(name, "#") {
+			return nil
+		}
+		g, err := newGreeting(name, s.expand, s.greet, s.color)
+		if err != nil {
+			return err
+		}
+		return fn(g)
+	})
+}
+
+func (s greetingStream) Text(w io.Writer) error {
+	return s.greetings(func(g greeting) error {
+		return g.Text(w)
+	})
+}
+
+// JSON writes a JSON array with one greeting object per input line. If
+// the stream fails after the first greeting, the array is still closed.
+func (s greetingStream) JSON(w io.Writer) error {
+	sep := "["
+	err := s.greetings(func(g greeting) error {
+		b, err := json.Marshal(g)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s%s", sep, b)
+		sep = ","
+		return err
+	})
+	switch {
+	case sep == "[" && err == nil:
+		_, err = io.WriteString(w, "[]\n")
+	case sep != "[":
+		// Close the array even if reading the names failed partway, so
+		// the greetings written so far are still valid JSON.
+		if _, werr := io.WriteString(w, "]\n"); err == nil {
+			err = werr
+		}
+	}
+	return err
+}
+
+// greetingList renders every greeting in stream at once through a list
+// template, as chosen by greet --names-format. Unlike greetingStream it
+// holds all the greetings in memory.
+type greetingList struct {
+	stream greetingStream
+	format string // a built-in format name or a template
+}
+
+func (l greetingList) Text(w io.Writer) error {
+	var items []examples.ListItem
+	err := l.stream.greetings(func(g greeting) error {
+		items = append(items, examples.ListItem{Name: greetedName(g.Name), Greeting: g.Greeting})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	var b strings.Builder
+	if err := examples.FormatList(&b, items, l.format); err != nil {
+		return fmt.Errorf("--names-format: %w", err)
+	}
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// JSON is never called: greet rejects --names-format with --json.
+func (l greetingList) JSON(w io.Writer) error { return l.Text(w) }
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestGreetConfigTemplate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	good := write("good.yaml", "greeting_template: \"Hey {{.Name}}, welcome!\"\n")
+	bad := write("bad.yaml", "greeting_template: \"Hey {{.Name\"\n")
+
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{"template", []string{"--config", good, "greet", "--name", "Alice"}, "", exitOK, "Hey Alice, welcome!\n", ""},
+		{"template default name", []string{"--config", good, "greet"}, "", exitOK, "Hey World, welcome!\n", ""},
+		{"template stdin", []string{"--config", good, "greet", "--stdin"}, "Bob\n\n", exitOK, "Hey Bob, welcome!\nHey World, welcome!\n", ""},
+		{"lang overrides template", []string{"--config", good, "greet", "--lang", "de"}, "", exitOK, "Hallo, Welt!\n", ""},
+		{"invalid template fails early", []string{"--config", bad, "version"}, "", exitError, "", "greeting_template"},
+		{"missing explicit config", []string{"--config", filepath.Join(dir, "nope.yaml"), "greet"}, "", exitError, "", "nope.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := newApp(strings.NewReader(tt.stdin), &out, &errOut).run(tt.args)
+			if code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, errOut.String())
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErrOut)
+			}
+		})
+	}
+}
+
+func TestGreetWithoutConfigUsesBuiltIn(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := run([]string{"greet", "--name", "Alice"}, &out, &errOut); code != exitOK {
+		t.Fatalf("run = %d (stderr: %s)", code, errOut.String())
+	}
+	if !strings.HasSuffix(out.String(), ", Alice!\n") {
+		t.Errorf("stdout = %q, want built-in greeting", out.String())
+	}
+}
+
+func TestGreetRandom(t *testing.T) {
+	input := "Ada\nBob\nCy\nDee\n"
+	want := "Greetings, Ada!\nHello, Bob!\nGreetings, Cy!\nHi, Dee!\n"
+
+	var out, errOut bytes.Buffer
+	code := newApp(strings.NewReader(input), &out, &errOut).run([]string{"greet", "--stdin", "--random", "--seed", "42"})
+	if code != exitOK {
+		t.Fatalf("greet --random = %d, want %d (stderr: %s)", code, exitOK, errOut.String())
+	}
+	if out.String() != want {
+		t.Errorf("stdout = %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	if code := run([]string{"greet", "--random", "--name", "Ada"}, &out, &errOut); code != exitOK {
+		t.Fatalf("greet --random without a seed = %d, want %d", code, exitOK)
+	}
+	if !strings.HasSuffix(out.String(), ", Ada!\n") {
+		t.Errorf("stdout = %q, want a greeting for Ada", out.String())
+	}
+
+	errOut.Reset()
+	if code := run([]string{"greet", "--random", "--lang", "es"}, &out, &errOut); code != exitUsage {
+		t.Errorf("greet --random --lang = %d, want %d", code, exitUsage)
+	}
+	if !strings.Contains(errOut.String(), "mutually exclusive") {
+		t.Errorf("stderr = %q, want a mutually exclusive error", errOut.String())
+	}
+}
+
+func TestGreetExpandEnv(t *testing.T) {
+	env := map[string]string{
+		"USER":  "Ada",
+		"FIRST": "Grace",
+		"LAST":  "Hopper",
+		"EVIL":  "Eve\x1b[31m\n",
+	}
+	getenv := func(key string) string { return env[key] }
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  string
+	}{
+		{"plain variable", []string{"--name", "$USER", "--expand-env"}, "", "Hola, Ada!\n"},
+		{"braced variables", []string{"--name", "${FIRST} ${LAST}", "--expand-env"}, "", "Hola, Grace Hopper!\n"},
+		{"undefined variable is empty", []string{"--name", "$NOPE", "--expand-env"}, "", "Hola, Mundo!\n"},
+		{"value is sanitized", []string{"--name", "$EVIL", "--expand-env"}, "", "Hola, Eve[31m!\n"},
+		{"off by default", []string{"--name", "$USER"}, "", "Hola, $USER!\n"},
+		{"stdin names", []string{"--stdin", "--expand-env"}, "$USER\n${FIRST}\n", "Hola, Ada!\nHola, Grace!\n"},
+		{"names format shows expanded names", []string{"--stdin", "--expand-env", "--names-format", "{{range .}}{{.Name}}: {{.Greeting}}\n{{end}}"}, "$USER\n$EVIL\n", "Ada: Hola, Ada!\nEve[31m: Hola, Eve[31m!\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			a := newApp(strings.NewReader(tt.stdin), &out, &errOut)
+			a.getenv = getenv
+			args := append([]string{"greet", "--lang", "es"}, tt.args...)
+			if code := a.run(args); code != exitOK {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", args, code, exitOK, errOut.String())
+			}
+			if out.String() != tt.want {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetNameFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "names.txt")
+	if err := os.WriteFile(path, []byte("# guests\nAda\n\n  # indented comment\n Grace \n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"text", []string{"greet", "--lang", "en", "--name-file", path}, "Hello, Ada!\nHello, World!\nHello, Grace!\n"},
+		{"json", []string{"--json", "greet", "--lang", "en", "--name-file", path},
+			`[{"name":"Ada","greeting":"Hello, Ada!"},{"name":"","greeting":"Hello, World!"},{"name":"Grace","greeting":"Hello, Grace!"}]` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != exitOK {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, exitOK, errOut.String())
+			}
+			if out.String() != tt.want {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetNameFileErrors(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantErr  string
+	}{
+		{"missing file", []string{"greet", "--name-file", missing}, exitError, "no such file"},
+		{"missing file with json", []string{"--json", "greet", "--name-file", missing}, exitError, "no such file"},
+		{"with stdin", []string{"greet", "--stdin", "--name-file", missing}, exitUsage, "mutually exclusive"},
+		{"with name", []string{"greet", "--name", "Ada", "--name-file", missing}, exitUsage, "--name cannot be combined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != tt.wantCode {
+				t.Errorf("run(%q) = %d, want %d", tt.args, code, tt.wantCode)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErr)
+			}
+			if out.Len() != 0 {
+				t.Errorf("stdout = %q, want empty", out.String())
+			}
+		})
+	}
+}
+
+func TestGreetProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  string
+		wantErr  string
+	}{
+		{"formal", []string{"greet", "--provider", "formal", "--name", "Alice"}, exitOK, "Good day to you, Alice.\n", ""},
+		{"casual", []string{"greet", "--provider", "casual", "--name", "Alice"}, exitOK, "yo Alice\n", ""},
+		{"with stdin", []string{"greet", "--provider", "casual", "--stdin"}, exitOK, "yo World\n", ""},
+		{"unknown", []string{"greet", "--provider", "pirate"}, exitUsage, "", `unknown provider "pirate": want one of casual, default, formal`},
+		{"with lang", []string{"greet", "--provider", "formal", "--lang", "es"}, exitUsage, "", "mutually exclusive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := newApp(strings.NewReader("\n"), &out, &errOut).run(tt.args); code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, errOut.String())
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGreetNamesFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{"markdown", []string{"greet", "--stdin", "--lang", "en", "--names-format", "markdown"}, "Ada\n\n", exitOK, "- Hello, Ada!\n- Hello, World!\n", ""},
+		{"html escapes", []string{"greet", "--stdin", "--lang", "en", "--names-format", "html"}, "<b>Bo</b>\n", exitOK, "<ul>\n  <li>Hello, &lt;b&gt;Bo&lt;/b&gt;!</li>\n</ul>\n", ""},
+		{"custom template", []string{"greet", "--stdin", "--lang", "es", "--names-format", "{{len .}}: {{range .}}{{.Name}} {{end}}"}, "Ada\nBob\n", exitOK, "2: Ada Bob ", ""},
+		{"invalid template", []string{"greet", "--stdin", "--names-format", "{{range"}, "Ada\n", exitError, "", "--names-format"},
+		{"needs a list", []string{"greet", "--name", "Ada", "--names-format", "html"}, "", exitUsage, "", "requires --stdin or --name-file"},
+		{"not with json", []string{"--json", "greet", "--stdin", "--names-format", "html"}, "", exitUsage, "", "cannot be combined with --json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := newApp(strings.NewReader(tt.stdin), &out, &errOut).run(tt.args); code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d; stderr: %s", tt.args, code, tt.wantCode, errOut.String())
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErrOut)
+			}
+		})
+	}
+}
+
+func TestGreetJSONReadError(t *testing.T) {
+	in := io.MultiReader(strings.NewReader("Ada\nBob\n"), iotest.ErrReader(errors.New("disk gone")))
+	var out, errOut bytes.Buffer
+	if code := newApp(in, &out, &errOut).run([]string{"--json", "greet", "--stdin"}); code != exitError {
+		t.Fatalf("run = %d, want %d (stderr: %s)", code, exitError, errOut.String())
+	}
+	var greetings []greeting
+	if err := json.Unmarshal(out.Bytes(), &greetings); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, out.String())
+	}
+	if len(greetings) != 2 {
+		t.Errorf("stdout holds %d greetings, want the 2 read before the error: %s", len(greetings), out.String())
+	}
+	if !strings.Contains(errOut.String(), "disk gone") {
+		t.Errorf("stderr = %q, want the read error", errOut.String())
+	}
+}
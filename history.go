@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/examples"
+	"github.com/ericbfriday/claude-go-containers/history"
+	"github.com/ericbfriday/claude-go-containers/internal/table"
+)
+
+func historyCommand() command {
+	return command{
+		name:    "history",
+		args:    "[-n N]",
+		summary: "List the most recent greetings made with greet --record",
+		setFlags: func(fs *flag.FlagSet) handler {
+			n := fs.Int("n", 10, "number of entries to show; 0 shows all")
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 {
+					return nil, usagef("unexpected arguments: %v", args)
+				}
+				if *n < 0 {
+					return nil, usagef("-n must not be negative")
+				}
+				store, err := a.historyStore()
+				if err != nil {
+					return nil, err
+				}
+				entries, err := store.Recent(*n)
+				if err != nil {
+					return nil, err
+				}
+				return historyList(entries), nil
+			}
+		},
+	}
+}
+
+// historyStore returns the greeting history kept at history.DefaultPath.
+func (a *app) historyStore() (history.Store, error) {
+	path, err := history.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return history.NewFileStore(path, a.logger), nil
+}
+
+// historyList is the result of the history command, oldest entry first.
+type historyList []history.Entry
+
+// Text writes a TIME, NAME, GREETING table in local time, or nothing for
+// an empty history. Names are sanitized again, so that entries recorded
+// before greet sanitized them cannot write control characters to the
+// terminal.
+func (l historyList) Text(w io.Writer) error {
+	if len(l) == 0 {
+		return nil
+	}
+	rows := [][]string{{"TIME", "NAME", "GREETING"}}
+	for _, e := range l {
+		rows = append(rows, []string{e.Time.Local().Format(time.DateTime), examples.SanitizeName(e.Name), e.Greeting})
+	}
+	return table.Write(w, rows, nil)
+}
+
+func (l historyList) JSON(w io.Writer) error {
+	if l == nil {
+		l = historyList{}
+	}
+	return writeJSON(w, l)
+}
@@ -0,0 +1,144 @@
+// Package history records the greetings the CLI has produced so they can
+// be listed later.
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Entry is one recorded greeting.
+type Entry struct {
+	Name     string    `json:"name"`
+	Greeting string    `json:"greeting"`
+	Time     time.Time `json:"timestamp"`
+}
+
+// Store keeps greeting history.
+type Store interface {
+	// Record adds entry to the history.
+	Record(entry Entry) error
+	// Recent returns up to n of the most recent entries, oldest first.
+	// n <= 0 means all of them.
+	Recent(n int) ([]Entry, error)
+}
+
+// DefaultPath returns the history file used by the CLI:
+// myapp/history.jsonl under $XDG_STATE_HOME, or under ~/.local/state if
+// that is unset or not an absolute path.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if !filepath.IsAbs(dir) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "myapp", "history.jsonl"), nil
+}
+
+// FileStore is a Store keeping one JSON object per line in a file. Writers
+// take an exclusive lock on the file, on platforms that support it, so
+// entries appended by concurrent processes never interleave; within a
+// process a FileStore is safe for concurrent use.
+type FileStore struct {
+	path   string
+	logger *slog.Logger
+	mu     sync.Mutex
+}
+
+// NewFileStore returns a FileStore for the file at path, which is created,
+// along with its directory, on the first Record. Lines that cannot be read
+// back are reported to logger; a nil logger discards them.
+func NewFileStore(path string, logger *slog.Logger) *FileStore {
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &FileStore{path: path, logger: logger}
+}
+
+// Record appends entry as a single line.
+func (s *FileStore) Record(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	// Closing the file releases the lock.
+	if err := lock(f, true); err != nil {
+		f.Close()
+		return fmt.Errorf("history: locking %s: %w", s.path, err)
+	}
+	_, err = f.Write(line)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	return nil
+}
+
+// Recent returns up to n of the most recent entries, oldest first, or all
+// of them if n <= 0. A missing file is an empty history. Blank lines are
+// ignored, and lines that are not valid entries, such as one cut short by
+// a crash, are skipped with a warning rather than failing the read.
+func (s *FileStore) Recent(n int) ([]Entry, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	defer f.Close() // also releases the lock
+	if err := lock(f, false); err != nil {
+		return nil, fmt.Errorf("history: locking %s: %w", s.path, err)
+	}
+
+	var entries []Entry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), math.MaxInt)
+	for lineNo := 1; sc.Scan(); lineNo++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			s.logger.Warn("skipping corrupt history line", "file", s.path, "line", lineNo, "err", err)
+			continue
+		}
+		if e.Greeting == "" || e.Time.IsZero() {
+			s.logger.Warn("skipping incomplete history line", "file", s.path, "line", lineNo)
+			continue
+		}
+		entries = append(entries, e)
+		if n > 0 && len(entries) > n {
+			entries = entries[1:]
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	return entries, nil
+}
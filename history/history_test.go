@@ -0,0 +1,137 @@
+package history
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func entry(i int) Entry {
+	name := fmt.Sprintf("user%d", i)
+	return Entry{Name: name, Greeting: "Hello, " + name + "!", Time: time.Date(2025, 1, 1, 0, 0, i, 0, time.UTC)}
+}
+
+func TestFileStoreRecent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "history.jsonl")
+	s := NewFileStore(path, nil)
+
+	if got, err := s.Recent(5); err != nil || got != nil {
+		t.Fatalf("Recent() on a missing file = %v, %v; want nil, nil", got, err)
+	}
+	for i := range 5 {
+		if err := s.Record(entry(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		n         int
+		wantFirst int
+		wantLen   int
+	}{
+		{3, 2, 3},
+		{5, 0, 5},
+		{10, 0, 5},
+		{0, 0, 5},
+	}
+	for _, tt := range tests {
+		got, err := s.Recent(tt.n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != tt.wantLen {
+			t.Fatalf("Recent(%d) returned %d entries, want %d", tt.n, len(got), tt.wantLen)
+		}
+		for i, e := range got {
+			if want := entry(tt.wantFirst + i); !e.Time.Equal(want.Time) || e.Name != want.Name || e.Greeting != want.Greeting {
+				t.Errorf("Recent(%d)[%d] = %+v, want %+v", tt.n, i, e, want)
+			}
+		}
+	}
+}
+
+func TestFileStoreSkipsBadLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	var logs bytes.Buffer
+	s := NewFileStore(path, slog.New(slog.NewTextHandler(&logs, nil)))
+	if err := s.Record(entry(1)); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("\n{\"name\":\"cut short\",\"greet\n{\"name\":\"no greeting\"}\n")
+	f.Close()
+	if err := s.Record(entry(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.Recent(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Name != "user1" || got[1].Name != "user2" {
+		t.Errorf("Recent() = %+v, want the two valid entries", got)
+	}
+	for _, want := range []string{"skipping corrupt history line", "line=3", "skipping incomplete history line", "line=4"} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log = %q, want it to contain %q", logs.String(), want)
+		}
+	}
+}
+
+func TestFileStoreConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	shared := NewFileStore(path, nil)
+	const writers, perWriter = 8, 25
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Half the writers have a store of their own, as separate
+			// processes would, so only the file lock keeps them apart.
+			s := shared
+			if w%2 == 1 {
+				s = NewFileStore(path, nil)
+			}
+			for i := range perWriter {
+				e := entry(i)
+				e.Greeting = strings.Repeat("x", 64*1024) // too long to be written in one go
+				if err := s.Record(e); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	var logs bytes.Buffer
+	got, err := NewFileStore(path, slog.New(slog.NewTextHandler(&logs, nil))).Recent(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != writers*perWriter || logs.Len() != 0 {
+		t.Errorf("read back %d entries, want %d; warnings: %s", len(got), writers*perWriter, logs.String())
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", "/state")
+	if got, err := DefaultPath(); err != nil || got != filepath.Join("/state", "myapp", "history.jsonl") {
+		t.Errorf("DefaultPath() = %q, %v", got, err)
+	}
+	t.Setenv("XDG_STATE_HOME", "relative")
+	t.Setenv("HOME", "/home/ada")
+	if got, err := DefaultPath(); err != nil || got != filepath.Join("/home/ada", ".local", "state", "myapp", "history.jsonl") {
+		t.Errorf("DefaultPath() with a relative XDG_STATE_HOME = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package history
+
+import "os"
+
+// lock does nothing on platforms without flock; writers in one process
+// are still serialized by FileStore.
+func lock(f *os.File, exclusive bool) error { return nil }
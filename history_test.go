@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/history"
+)
+
+func TestHistory(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	runOK := func(stdin string, args ...string) string {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		if code := newApp(strings.NewReader(stdin), &out, &errOut).run(args); code != exitOK {
+			t.Fatalf("run(%q) = %d, want %d; stderr: %s", args, code, exitOK, errOut.String())
+		}
+		return out.String()
+	}
+
+	if out := runOK("", "history"); out != "" {
+		t.Errorf("history before any greeting = %q, want empty", out)
+	}
+	if out := runOK("", "--json", "history"); out != "[]\n" {
+		t.Errorf("history --json before any greeting = %q, want []", out)
+	}
+
+	runOK("", "greet", "--name", "Ada", "--lang", "en", "--record")
+	runOK("", "greet", "--name", "Unrecorded")
+	runOK("Bob\nCarol\n", "greet", "--stdin", "--lang", "fr", "--record")
+
+	var entries []history.Entry
+	if err := json.Unmarshal([]byte(runOK("", "--json", "history", "-n", "2")), &entries); err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Name != "Bob" || entries[0].Greeting != "Bonjour, Bob!" || entries[1].Name != "Carol" || entries[0].Time.IsZero() {
+		t.Errorf("history -n 2 = %+v, want Bob's and Carol's greetings", entries)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(runOK("", "history"), "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "TIME") || !strings.HasSuffix(lines[1], "Ada    Hello, Ada!") {
+		t.Errorf("history =\n%s\nwant a header and three entries, Ada's first", strings.Join(lines, "\n"))
+	}
+}
+
+func TestHistoryRecordsGreetedName(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	a := newApp(strings.NewReader(""), &bytes.Buffer{}, &bytes.Buffer{})
+	a.getenv = func(key string) string {
+		if key == "WHO" {
+			return "Alice"
+		}
+		return ""
+	}
+	for _, args := range [][]string{
+		{"greet", "--lang", "en", "--record", "--name", "Bob\n\x1b[31mX"},
+		{"greet", "--lang", "en", "--record", "--expand-env", "--name", "$WHO"},
+	} {
+		if code := a.run(args); code != exitOK {
+			t.Fatalf("run(%q) = %d, want %d", args, code, exitOK)
+		}
+	}
+
+	store, err := a.historyStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An entry written before names were sanitized on recording.
+	if err := store.Record(history.Entry{Name: "Eve\x1b[2J", Greeting: "Hello, Eve!", Time: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	a.out = &out
+	if code := a.run([]string{"history"}); code != exitOK {
+		t.Fatalf("history = %d, want %d", code, exitOK)
+	}
+	if strings.ContainsAny(out.String(), "\x1b\r") {
+		t.Errorf("history wrote control characters:\n%q", out.String())
+	}
+
+	entries, err := store.Recent(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries[:2] {
+		names = append(names, e.Name)
+	}
+	if want := []string{"Bob[31mX", "Alice"}; !slices.Equal(names, want) {
+		t.Errorf("recorded names %q, want %q", names, want)
+	}
+	if !strings.Contains(out.String(), "Eve[2J ") {
+		t.Errorf("history =\n%s\nwant Eve's stored entry shown sanitized", out.String())
+	}
+}
+
+func TestHistoryInvalidCount(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := run([]string{"history", "-n", "-1"}, &out, &errOut); code != exitUsage {
+		t.Errorf("history -n -1 = %d, want %d", code, exitUsage)
+	}
+}
@@ -0,0 +1,51 @@
+// Package argv builds the argument vectors of subprocesses.
+//
+// Arguments are always handed to exec.Command as separate elements of the
+// new process's argv and never joined into a command line for a shell, so
+// spaces, quotes and shell metacharacters in user input arrive unchanged
+// and cannot be interpreted. The one thing argv cannot carry is a NUL
+// byte, which the operating system reads as the end of the argument; Args
+// rejects it before the process is started.
+package argv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNUL is wrapped by the error Build returns for an argument containing
+// a NUL byte.
+var ErrNUL = errors.New("argument contains a NUL byte")
+
+// Args builds an argument vector, starting with the program to run. The
+// zero value is empty.
+type Args struct {
+	args []string
+}
+
+// New returns an Args holding bin followed by args.
+func New(bin string, args ...string) *Args {
+	return new(Args).Add(bin).Add(args...)
+}
+
+// Add appends args, each as a single argument.
+func (a *Args) Add(args ...string) *Args {
+	a.args = append(a.args, args...)
+	return a
+}
+
+// Build returns a copy of the arguments. It returns an error wrapping
+// ErrNUL, naming the first offending argument by position, if any
+// contains a NUL byte, and an error if there are none at all.
+func (a *Args) Build() ([]string, error) {
+	if len(a.args) == 0 {
+		return nil, errors.New("no program to run")
+	}
+	for i, arg := range a.args {
+		if strings.IndexByte(arg, 0) >= 0 {
+			return nil, fmt.Errorf("argument %d (%q): %w", i, arg, ErrNUL)
+		}
+	}
+	return append([]string(nil), a.args...), nil
+}
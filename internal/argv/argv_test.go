@@ -0,0 +1,55 @@
+package argv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    *Args
+		want    []string
+		wantNUL bool
+	}{
+		{"bin only", New("claude"), []string{"claude"}, false},
+		{"args kept whole", New("claude", "--model", "a b").Add(`it's "quoted"`, "$HOME; rm -rf /", ""), []string{"claude", "--model", "a b", `it's "quoted"`, "$HOME; rm -rf /", ""}, false},
+		{"zero value", new(Args).Add("opencode", "run"), []string{"opencode", "run"}, false},
+		{"NUL in argument", New("claude", "ok", "a\x00b"), nil, true},
+		{"NUL in bin", New("cla\x00ude"), nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.args.Build()
+			if tt.wantNUL {
+				if !errors.Is(err, ErrNUL) {
+					t.Fatalf("Build() error = %v, want ErrNUL", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	if _, err := new(Args).Build(); err == nil {
+		t.Error("Build() of no arguments error = nil")
+	}
+}
+
+func TestBuildCopies(t *testing.T) {
+	a := New("claude", "--print")
+	got, _ := a.Build()
+	got[1] = "changed"
+	if again, _ := a.Build(); again[1] != "--print" {
+		t.Errorf("Build() = %q after modifying an earlier result", again)
+	}
+}
@@ -0,0 +1,68 @@
+// Package atomicfile writes files so that readers, and anyone looking
+// after a crash, see either the old contents or the complete new ones.
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// File is a temporary file in the directory of the file it replaces. Its
+// contents take the place of that file only once Commit is called.
+type File struct {
+	*os.File
+	path string
+	done bool
+}
+
+// Create creates the directories leading to path, if necessary, and
+// returns a File that will replace path when committed.
+func Create(path string) (*File, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &File{File: f, path: path}, nil
+}
+
+// Commit flushes the file to stable storage, closes it and renames it
+// over the destination, with mode 0644 rather than the temporary file's
+// 0600. Flushing first means that after a crash the destination holds
+// either its old contents or the complete new ones, never a partial file.
+// If anything fails the temporary file is removed and the destination is
+// left as it was.
+func (f *File) Commit() error {
+	if f.done {
+		return os.ErrClosed
+	}
+	f.done = true
+	err := f.Chmod(0o644)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), f.path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+	}
+	return err
+}
+
+// Abort closes and removes the file, leaving the destination as it was.
+// It does nothing after Commit, so it can be deferred.
+func (f *File) Abort() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	f.Close()
+	return os.Remove(f.Name())
+}
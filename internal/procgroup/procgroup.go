@@ -0,0 +1,9 @@
+// Package procgroup makes canceled subprocesses take their children down
+// with them, so interrupting a command never leaves orphaned tools behind.
+package procgroup
+
+import "time"
+
+// DefaultGrace is how long a process group has to exit after SIGTERM
+// before it is sent SIGKILL.
+const DefaultGrace = 2 * time.Second
@@ -0,0 +1,73 @@
+package procgroup
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestConfigure(t *testing.T) {
+	tests := []struct {
+		name string
+		// script forks a child that writes its pid; handlers may write to
+		// $MARKER when they receive SIGTERM.
+		script     string
+		wantMarker bool
+	}{
+		{
+			name:       "group exits on SIGTERM",
+			script:     `sh -c 'trap "echo term > \"$MARKER\"; exit 0" TERM; echo $$ > "$PIDFILE"; while :; do sleep 0.05; done' & wait`,
+			wantMarker: true,
+		},
+		{
+			name:       "member outliving its leader is killed",
+			script:     `trap "echo term > \"$MARKER\"; exit 0" TERM; sh -c 'trap "" TERM; echo $$ > "$PIDFILE"; while :; do sleep 0.05; done' & wait`,
+			wantMarker: true,
+		},
+		{
+			name:   "group ignoring SIGTERM is killed",
+			script: `trap "" TERM; sh -c 'trap "" TERM; echo $$ > "$PIDFILE"; while :; do sleep 0.05; done' & wait`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			pidFile := filepath.Join(dir, "child.pid")
+			marker := filepath.Join(dir, "marker")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			cmd := exec.CommandContext(ctx, "sh", "-c", tt.script)
+			cmd.Env = append(os.Environ(), "PIDFILE="+pidFile, "MARKER="+marker)
+			Configure(cmd, 100*time.Millisecond)
+			if err := cmd.Start(); err != nil {
+				t.Fatal(err)
+			}
+			done := make(chan error, 1)
+			go func() { done <- cmd.Wait() }()
+			pid := stub.WaitPID(t, pidFile)
+
+			cancel()
+			select {
+			case err := <-done:
+				if err == nil {
+					t.Error("Wait() error = nil after cancellation")
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Wait() did not return after cancellation")
+			}
+			if !stub.WaitExit(pid) {
+				t.Errorf("child process %d still running after cancellation", pid)
+			}
+			if _, err := os.Stat(marker); (err == nil) != tt.wantMarker {
+				t.Errorf("SIGTERM handler ran = %v, want %v", err == nil, tt.wantMarker)
+			}
+		})
+	}
+}
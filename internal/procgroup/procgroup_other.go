@@ -0,0 +1,15 @@
+//go:build !unix
+
+package procgroup
+
+import (
+	"os/exec"
+	"time"
+)
+
+// Configure only bounds how long Wait may block after cancellation on
+// platforms without POSIX process groups; cancellation kills just the
+// direct child.
+func Configure(cmd *exec.Cmd, grace time.Duration) {
+	cmd.WaitDelay = grace + time.Second
+}
@@ -0,0 +1,38 @@
+//go:build unix
+
+package procgroup
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// Configure starts cmd in its own process group and replaces cancellation
+// with a graceful stop of the whole group: when cmd's context is done the
+// group is sent SIGTERM, then SIGKILL if any member is still running grace
+// later. It also sets cmd.WaitDelay so Wait returns shortly after the group
+// is killed even if a member kept an output pipe open. Configure must be
+// called before cmd is started.
+//
+// Because the group is separate, a terminal's Ctrl-C no longer reaches the
+// children directly; callers should cancel the context on SIGINT instead.
+func Configure(cmd *exec.Cmd, grace time.Duration) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		pgid := cmd.Process.Pid
+		err := syscall.Kill(-pgid, syscall.SIGTERM)
+		// Members that ignore SIGTERM, including ones that outlive the
+		// leader, are killed once the grace period ends. The group ID
+		// cannot be reused while any member is alive, so surviving
+		// members are still reached through it.
+		time.AfterFunc(grace, func() { syscall.Kill(-pgid, syscall.SIGKILL) })
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+		return err
+	}
+	cmd.WaitDelay = grace + time.Second
+}
@@ -0,0 +1,35 @@
+// Package shellquote renders argument vectors as POSIX shell command lines
+// that can be pasted into a shell and run unchanged.
+package shellquote
+
+import "strings"
+
+// Quote returns s in a form the shell reads back as the single word s.
+// Words made only of characters the shell never interprets are returned
+// as is; anything else is wrapped in single quotes.
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if strings.IndexFunc(s, needsQuoting) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Join quotes each of args and joins them with spaces.
+func Join(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = Quote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func needsQuoting(r rune) bool {
+	switch {
+	case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		return false
+	}
+	return !strings.ContainsRune("@%+=:,./_-", r)
+}
@@ -0,0 +1,49 @@
+package shellquote
+
+import (
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", "''"},
+		{"plain", "plain"},
+		{"/usr/local/bin/claude", "/usr/local/bin/claude"},
+		{"--model=x", "--model=x"},
+		{"two words", "'two words'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME", "'$HOME'"},
+		{"a;b", "'a;b'"},
+		{"line\nbreak", "'line\nbreak'"},
+		{"*", "'*'"},
+		{"héllo", "'héllo'"},
+	}
+	for _, tt := range tests {
+		if got := Quote(tt.in); got != tt.want {
+			t.Errorf("Quote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestJoinRoundTrips checks that the shell splits a joined line back into
+// the original arguments.
+func TestJoinRoundTrips(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	args := []string{"plain", "", "two words", "it's", "$HOME", "`date`", "a\\b", "line\nbreak", `"quoted"`, "*"}
+	out, err := exec.Command("sh", "-c", `printf '%s\0' `+Join(args...)).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	if !slices.Equal(got, args) {
+		t.Errorf("sh split %s into %q, want %q", Join(args...), got, args)
+	}
+}
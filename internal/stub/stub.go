@@ -0,0 +1,33 @@
+// Package stub creates fake executables for tests that exercise code
+// which shells out to external tools.
+package stub
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// Write creates an executable shell script called name in dir whose body is
+// body, and returns its path. The test is skipped on platforms without a
+// POSIX shell.
+func Write(t testing.TB, dir, name, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("stub executables require a POSIX shell")
+	}
+	path := filepath.Join(dir, name)
+	script := "#!/bin/sh\n" + body + "\n"
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// PrependPath puts dir first on PATH for the duration of the test, so stubs
+// shadow real tools while standard utilities remain available.
+func PrependPath(t testing.TB, dir string) {
+	t.Helper()
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
@@ -0,0 +1,43 @@
+// Package table renders rows of text as aligned columns.
+package table
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Write writes rows as columns separated by two spaces, padding each cell
+// to the widest value in its column. Trailing spaces are trimmed. style, if
+// not nil, may decorate a cell after its width has been measured, so escape
+// sequences don't skew alignment.
+func Write(w io.Writer, rows [][]string, style func(row, col int, cell string) string) error {
+	var widths []int
+	for _, row := range rows {
+		for col, cell := range row {
+			if col == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[col] = max(widths[col], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		line := make([]string, len(row))
+		for col, cell := range row {
+			pad := ""
+			if col < len(row)-1 {
+				pad = strings.Repeat(" ", widths[col]-utf8.RuneCountInString(cell))
+			}
+			if style != nil {
+				cell = style(i, col, cell)
+			}
+			line[col] = cell + pad
+		}
+		b.WriteString(strings.TrimRight(strings.Join(line, "  "), " "))
+		b.WriteByte('\n')
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,43 @@
+package table
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name  string
+		rows  [][]string
+		style func(row, col int, cell string) string
+		want  string
+	}{
+		{"empty", nil, nil, ""},
+		{"aligns to widest cell", [][]string{{"A", "B", "C"}, {"long", "x", "y"}}, nil, "A     B  C\nlong  x  y\n"},
+		{"counts runes not bytes", [][]string{{"héllo", "x"}, {"hi", "y"}}, nil, "héllo  x\nhi     y\n"},
+		{"trims trailing space", [][]string{{"a", ""}, {"b", "c"}}, nil, "a\nb  c\n"},
+		{
+			"style does not affect width",
+			[][]string{{"A", "B"}, {"ok", "x"}},
+			func(row, col int, cell string) string {
+				if row > 0 && col == 0 {
+					return "[" + cell + "]"
+				}
+				return cell
+			},
+			"A   B\n[ok]  x\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := Write(&b, tt.rows, tt.style); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("Write() = %q, want %q", b.String(), tt.want)
+			}
+		})
+	}
+}
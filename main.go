@@ -1,14 +1,393 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/color"
+	"github.com/ericbfriday/claude-go-containers/config"
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/exitcode"
+	"github.com/ericbfriday/claude-go-containers/internal/atomicfile"
+	"github.com/ericbfriday/claude-go-containers/metrics"
+	"github.com/ericbfriday/claude-go-containers/progress"
+	"github.com/ericbfriday/claude-go-containers/server"
+)
+
+// progName is the name the CLI uses when printing usage and diagnostics.
+const progName = "myapp"
+
+// Exit codes returned by run.
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+	// exitTimeout is returned when --timeout expires, matching timeout(1).
+	exitTimeout = 124
 )
 
 func main() {
-	fmt.Println("Hello from Go + AI Development Environment!")
-	fmt.Println("This workspace includes:")
-	fmt.Println("  • Go development tools (latest)")
-	fmt.Println("  • Claude CLI for quick AI assistance")
-	fmt.Println("  • OpenCode AI for terminal-based coding workflows")
-	fmt.Println("\nTry: 'claude --help' or 'opencode --help' to get started!")
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run parses args, dispatches to the matching subcommand and returns the
+// process exit code. All output goes to out and errOut so it can be tested.
+func run(args []string, out, errOut io.Writer) int {
+	return newApp(os.Stdin, out, errOut).run(args)
+}
+
+// app holds the I/O streams and global options shared by every subcommand.
+type app struct {
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+
+	// isTerminal reports whether a writer is a terminal. It decides
+	// whether text output is colored; tests replace it.
+	isTerminal func(io.Writer) bool
+	// getenv looks up environment variables for the configuration and for
+	// greet --expand-env; tests replace it.
+	getenv func(key string) string
+
+	json   bool // render command output as JSON
+	color  color.Colorizer
+	logger *slog.Logger
+	config *config.Config
+	// configOptions are the sources config was loaded from, kept so serve
+	// can reload it.
+	configOptions config.Options
+	// timeout bounds each tool-invoking operation; see toolContext. Zero
+	// means no limit.
+	timeout time.Duration
+
+	// dryRun, if set, receives the command lines of subprocesses that
+	// would have been run; nothing is executed.
+	dryRun io.Writer
+	// metrics counts the work done by commands and tool invocations. It
+	// is exposed by serve --metrics.
+	metrics *metrics.Registry
+	// quiet suppresses output that is neither a command's result nor an
+	// error, such as the banner and progress; see --quiet.
+	quiet bool
+	// requestID identifies this invocation in every log record and in
+	// the contexts of the tools it runs.
+	requestID string
+	// environment caches checkEnvironment, so that doctor and serve's
+	// /readyz, and repeated commands in repl or watch, share one set of
+	// version probes.
+	environment *doctor.EnvironmentCache
+}
+
+func newApp(in io.Reader, out, errOut io.Writer) *app {
+	a := &app{
+		in:         in,
+		out:        out,
+		errOut:     errOut,
+		isTerminal: color.IsTerminal,
+		getenv:     os.Getenv,
+		logger:     slog.New(slog.DiscardHandler),
+		config:     config.Default(),
+		metrics:    metrics.NewRegistry(),
+		requestID:  server.NewRequestID(),
+	}
+	a.environment = doctor.NewEnvironmentCache(a.checkEnvironment, doctor.DefaultCacheTTL)
+	return a
+}
+
+// globalOptions holds the flags accepted before the command name. Those
+// that set a configuration key, such as logLevel, are only parsed here;
+// run resolves them with the other configuration sources.
+type globalOptions struct {
+	json       bool
+	logLevel   slog.Level
+	logFormat  string
+	noColor    bool
+	configPath string
+	dryRun     bool
+	timeout    time.Duration
+	output     string
+	quiet      bool
+	verbose    bool
+}
+
+// newGlobalFlags returns the flag set for the global flags, bound to opts.
+func newGlobalFlags(opts *globalOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet(progName, flag.ContinueOnError)
+	fs.BoolVar(&opts.json, "json", false, "write command output as JSON")
+	fs.TextVar(&opts.logLevel, "log-level", slog.LevelWarn, "minimum log level: debug, info, warn or error (env MYAPP_LOG_LEVEL)")
+	fs.StringVar(&opts.logFormat, "log-format", "text", "log format: text or json (env MYAPP_LOG_FORMAT)")
+	fs.BoolVar(&opts.noColor, "no-color", false, "disable colored output (also set by NO_COLOR)")
+	fs.StringVar(&opts.configPath, "config", "", "configuration file (default $MYAPP_CONFIG or $XDG_CONFIG_HOME/myapp/config.yaml)")
+	fs.DurationVar(&opts.timeout, "timeout", config.DefaultTimeout, "limit for each claude, opencode or doctor operation; 0 disables (env MYAPP_TIMEOUT)")
+	fs.StringVar(&opts.output, "output", "", "write command output to this `file` instead of stdout, replacing it only if the command succeeds; - means stdout")
+	fs.BoolVar(&opts.quiet, "quiet", false, "print only command results and errors: no banner, progress or log records below error")
+	fs.BoolVar(&opts.verbose, "verbose", false, "also log diagnostics such as resolved tool paths and timings (log level debug)")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "print the commands that would run claude, opencode or version probes to stderr instead of running them")
+	return fs
+}
+
+func (a *app) run(args []string) (code int) {
+	var opts globalOptions
+	global := newGlobalFlags(&opts)
+	global.SetOutput(a.errOut)
+	global.Usage = func() { a.usage(global.Output(), global) }
+	if err := global.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+	args = global.Args()
+
+	flags, flagNames, err := verbosityFlags(global, opts)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "%s: %v\n", progName, err)
+		return exitUsage
+	}
+	a.configOptions = config.Options{Path: opts.configPath, Flags: flags, FlagNames: flagNames, Getenv: a.getenv}
+	cfg, err := config.Load(a.configOptions)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "%s: %v\n", progName, err)
+		var serr *config.SettingError
+		if errors.As(err, &serr) && serr.Origin.Source == config.SourceFlag {
+			return exitUsage
+		}
+		return exitError
+	}
+	a.config = cfg
+	logger, err := newLogger(a.errOut, cfg.LogLevel, cfg.LogFormat, a.requestID)
+	if err != nil {
+		fmt.Fprintf(a.errOut, "%s: %v\n", progName, err)
+		return exitUsage
+	}
+	a.logger = logger
+	a.quiet = opts.quiet
+	a.json = opts.json
+	a.timeout = cfg.Timeout
+	if opts.output != "" && opts.output != "-" {
+		f, err := atomicfile.Create(opts.output)
+		if err != nil {
+			fmt.Fprintf(a.errOut, "%s: %v\n", progName, err)
+			return exitError
+		}
+		a.out = f
+		defer func() {
+			if code != exitOK {
+				f.Abort()
+				return
+			}
+			if err := f.Commit(); err != nil {
+				fmt.Fprintf(a.errOut, "%s: %v\n", progName, err)
+				code = exitError
+			}
+		}()
+	}
+	if opts.dryRun {
+		a.dryRun = a.errOut
+	}
+	a.color = color.Colorizer{Enabled: !opts.noColor && !a.json && color.Enabled(a.out, a.isTerminal)}
+
+	if len(args) == 0 {
+		if a.json {
+			fmt.Fprintf(a.errOut, "%s: a command is required with --json\n", progName)
+			return exitUsage
+		}
+		if !a.quiet {
+			printBanner(a.out)
+		}
+		return exitOK
+	}
+
+	name, rest := args[0], args[1:]
+	if name == "help" {
+		a.usage(a.out, global)
+		return exitOK
+	}
+
+	cmd, ok := lookup(name)
+	if !ok {
+		fmt.Fprintf(a.errOut, "%s: unknown command %q%s\n\n", progName, name, didYouMean(name))
+		a.usage(a.errOut, global)
+		return exitUsage
+	}
+	return a.dispatch(cmd, rest)
+}
+
+// progress returns a Progress drawn on standard error when that is a
+// terminal, or nil, which draws nothing, under --quiet. Commands finish it
+// before returning their output.
+func (a *app) progress() *progress.Progress {
+	if a.quiet {
+		return nil
+	}
+	return progress.New(a.errOut, a.isTerminal)
+}
+
+// verbosityFlags returns the configuration flags in fs with --quiet or
+// --verbose applied as a log level, and the names of the flags that set
+// it. It is an error to give both, or either with --log-level.
+func verbosityFlags(fs *flag.FlagSet, opts globalOptions) (flags, names map[string]string, err error) {
+	flags = configFlags(fs)
+	if opts.quiet && opts.verbose {
+		return nil, nil, errors.New("--quiet and --verbose are mutually exclusive")
+	}
+	level, name := "", ""
+	switch {
+	case opts.quiet:
+		level, name = "error", "--quiet"
+	case opts.verbose:
+		level, name = "debug", "--verbose"
+	default:
+		return flags, nil, nil
+	}
+	if _, ok := flags["log_level"]; ok {
+		return nil, nil, fmt.Errorf("%s cannot be combined with --log-level", name)
+	}
+	flags["log_level"] = level
+	return flags, map[string]string{"log_level": name}, nil
+}
+
+// configFlags returns the values of the global flags in fs that set a
+// configuration key and were given explicitly, keyed by configuration key.
+func configFlags(fs *flag.FlagSet) map[string]string {
+	flags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		for _, key := range config.Keys() {
+			if config.FlagName(key) == f.Name {
+				flags[key] = f.Value.String()
+			}
+		}
+	})
+	return flags
+}
+
+// dispatch parses the subcommand's own flags, runs its handler and renders
+// the resulting Output in the selected format.
+func (a *app) dispatch(cmd command, args []string) int {
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(a.errOut)
+	h := cmd.setFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s %s\n\n%s\n", progName, cmd.name, cmd.args, cmd.summary)
+		if hasFlags(fs) {
+			fmt.Fprintln(fs.Output(), "\nFlags:")
+			fs.PrintDefaults()
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	a.logger.Debug("running command", "command", cmd.name, "args", fs.Args())
+	start := time.Now()
+	defer func() { a.logger.Debug("command finished", "command", cmd.name, "duration", time.Since(start)) }()
+	result, err := h(a, fs.Args())
+	if result != nil {
+		if rerr := a.render(result); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(a.errOut, "%s: %v\n", cmd.name, err)
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+// newLogger returns a logger writing records at or above level to w in the
+// given format. Each record carries a request_id: that of the HTTP request
+// being served, if any, and requestID otherwise.
+func newLogger(w io.Writer, level slog.Level, format, requestID string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch format {
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q: want text or json", format)
+	}
+	return slog.New(server.NewLogHandler(h, requestID)), nil
+}
+
+func (a *app) render(o Output) error {
+	if a.json {
+		return o.JSON(a.out)
+	}
+	return o.Text(a.out)
+}
+
+func (a *app) usage(w io.Writer, global *flag.FlagSet) {
+	fmt.Fprintf(w, "Usage: %s [global flags] <command> [flags] [args]\n\nCommands:\n", progName)
+	for _, cmd := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w, "\nGlobal flags:")
+	global.SetOutput(w)
+	global.PrintDefaults()
+	global.SetOutput(a.errOut)
+	fmt.Fprintf(w, "\nRun '%s <command> -h' for help on a command.\n", progName)
+}
+
+func hasFlags(fs *flag.FlagSet) bool {
+	n := 0
+	fs.VisitAll(func(*flag.Flag) { n++ })
+	return n > 0
+}
+
+// usageError reports invalid command-line usage. It maps to exitUsage.
+type usageError struct{ msg string }
+
+func (e *usageError) Error() string { return e.msg }
+
+func usagef(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// timeoutError is the cause of a context canceled by --timeout. It
+// matches context.DeadlineExceeded, and its message replaces the generic
+// "context deadline exceeded" in errors that use context.Cause.
+type timeoutError struct{ after time.Duration }
+
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("timed out after %v (see --timeout)", e.after)
+}
+
+func (e *timeoutError) Is(target error) bool { return target == context.DeadlineExceeded }
+
+// exitCode maps an error returned by a handler to a process exit code. A
+// wrapped tool that exited non-zero passes its own code through, so
+// scripts can branch on it.
+func exitCode(err error) int {
+	var uerr *usageError
+	if errors.As(err, &uerr) {
+		return exitUsage
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return exitTimeout
+	}
+	if code, ok := exitcode.From(err); ok {
+		return code
+	}
+	return exitError
+}
+
+// printBanner writes the welcome message shown when no command is given.
+func printBanner(w io.Writer) {
+	fmt.Fprintln(w, "Hello from Go + AI Development Environment!")
+	fmt.Fprintln(w, "This workspace includes:")
+	fmt.Fprintln(w, "  • Go development tools (latest)")
+	fmt.Fprintln(w, "  • Claude CLI for quick AI assistance")
+	fmt.Fprintln(w, "  • OpenCode AI for terminal-based coding workflows")
+	fmt.Fprintln(w, "\nTry: 'claude --help' or 'opencode --help' to get started!")
 }
@@ -0,0 +1,648 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/color"
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+	"github.com/ericbfriday/claude-go-containers/metrics"
+)
+
+// TestMain isolates the tests from the developer's own configuration file,
+// response cache and greeting history.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "myapp-test-config")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Setenv("XDG_CACHE_HOME", dir)
+	os.Setenv("XDG_STATE_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{"no args prints banner", nil, exitOK, "Hello from Go + AI Development Environment!", ""},
+		{"help", []string{"help"}, exitOK, "Commands:", ""},
+		{"greet default", []string{"greet"}, exitOK, ", World!\n", ""},
+		{"greet with name", []string{"greet", "--name", "Alice"}, exitOK, ", Alice!\n", ""},
+		{"greet localized", []string{"greet", "--name", "Alice", "--lang", "es"}, exitOK, "Hola, Alice!\n", ""},
+		{"greet localized default name", []string{"greet", "--lang", "fr"}, exitOK, "Bonjour, Monde!\n", ""},
+		{"greet extra args", []string{"greet", "Alice"}, exitUsage, "", "unexpected arguments"},
+		{"add", []string{"add", "2", "3"}, exitOK, "5\n", ""},
+		{"add negative", []string{"add", "--", "-2", "-3"}, exitOK, "-5\n", ""},
+		{"add no operands", []string{"add"}, exitOK, "0\n", ""},
+		{"add single operand", []string{"add", "2"}, exitOK, "2\n", ""},
+		{"add many operands", []string{"add", "1", "2", "3", "4"}, exitOK, "10\n", ""},
+		{"add overflow", []string{"add", strconv.Itoa(math.MaxInt), "1"}, exitError, "", "integer overflow"},
+		{"add underflow", []string{"add", "--", strconv.Itoa(math.MinInt), "-1"}, exitError, "", "integer overflow"},
+		{"add invalid operand", []string{"add", "2", "x"}, exitUsage, "", `invalid integer "x"`},
+		{"version", []string{"version"}, exitOK, progName + " ", ""},
+		{"unknown flag", []string{"greet", "--bogus"}, exitUsage, "", "flag provided but not defined"},
+		{"invalid log level", []string{"--log-level", "loud", "version"}, exitUsage, "", "invalid value"},
+		{"invalid log format", []string{"--log-format", "xml", "version"}, exitUsage, "", "invalid log format"},
+		{"quiet by default", []string{"version"}, exitOK, "", ""},
+		{"unknown command", []string{"bogus"}, exitUsage, "", `unknown command "bogus"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := run(tt.args, &out, &errOut)
+			if code != tt.wantCode {
+				t.Errorf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, errOut.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("run(%q) stdout = %q, want it to contain %q", tt.args, out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("run(%q) stderr = %q, want it to contain %q", tt.args, errOut.String(), tt.wantErrOut)
+			}
+		})
+	}
+}
+
+func TestRunUnknownCommandPrintsUsageToStderr(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := run([]string{"frobnicate"}, &out, &errOut); code == exitOK {
+		t.Fatalf("run returned %d, want non-zero", code)
+	}
+	if out.Len() != 0 {
+		t.Errorf("stdout = %q, want empty", out.String())
+	}
+	if !strings.Contains(errOut.String(), "Usage:") {
+		t.Errorf("stderr = %q, want usage", errOut.String())
+	}
+}
+
+func TestGreetStdin(t *testing.T) {
+	long := strings.Repeat("x", 1<<20)
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"one per line", "Alice\nBob\n", []string{", Alice!", ", Bob!"}},
+		{"trims whitespace", "  Alice \t\n", []string{", Alice!"}},
+		{"blank line falls back", "Alice\n\n   \nBob", []string{", Alice!", ", World!", ", World!", ", Bob!"}},
+		{"very long line is read whole", long + "y\nBob\n", []string{", " + long[:255] + "…!", ", Bob!"}},
+		{"empty input", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := newApp(strings.NewReader(tt.input), &out, &errOut).run([]string{"greet", "--stdin"})
+			if code != exitOK {
+				t.Fatalf("greet --stdin = %d, want %d (stderr: %s)", code, exitOK, errOut.String())
+			}
+			lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			if len(tt.want) == 0 {
+				if out.Len() != 0 {
+					t.Errorf("stdout = %q, want empty", out.String())
+				}
+				return
+			}
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d lines, want %d", len(lines), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.HasSuffix(lines[i], want) {
+					t.Errorf("line %d = %.40q, want suffix %.40q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestGreetStdinRejectsName(t *testing.T) {
+	var out, errOut bytes.Buffer
+	code := newApp(strings.NewReader("Bob\n"), &out, &errOut).run([]string{"greet", "--stdin", "--name", "Alice"})
+	if code != exitUsage {
+		t.Errorf("code = %d, want %d", code, exitUsage)
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		wantCode int
+		check    func(t *testing.T, out string)
+	}{
+		{
+			name: "greet", args: []string{"--json", "greet", "--name", "Alice"}, wantCode: exitOK,
+			check: func(t *testing.T, out string) {
+				var g greeting
+				if err := json.Unmarshal([]byte(out), &g); err != nil {
+					t.Fatal(err)
+				}
+				if g.Name != "Alice" || !strings.HasSuffix(g.Greeting, ", Alice!") {
+					t.Errorf("greeting = %+v", g)
+				}
+			},
+		},
+		{
+			name: "greet stdin", args: []string{"--json", "greet", "--stdin"}, stdin: "Alice\n\nBob\n", wantCode: exitOK,
+			check: func(t *testing.T, out string) {
+				var gs []greeting
+				if err := json.Unmarshal([]byte(out), &gs); err != nil {
+					t.Fatal(err)
+				}
+				if len(gs) != 3 || gs[0].Name != "Alice" || gs[1].Name != "" || gs[2].Name != "Bob" {
+					t.Errorf("greetings = %+v", gs)
+				}
+			},
+		},
+		{
+			name: "greet empty stdin", args: []string{"--json", "greet", "--stdin"}, wantCode: exitOK,
+			check: func(t *testing.T, out string) {
+				if out != "[]\n" {
+					t.Errorf("stdout = %q, want %q", out, "[]\n")
+				}
+			},
+		},
+		{
+			name: "add", args: []string{"--json", "add", "2", "3"}, wantCode: exitOK,
+			check: func(t *testing.T, out string) {
+				if want := `{"operands":[2,3],"result":5}` + "\n"; out != want {
+					t.Errorf("stdout = %q, want %q", out, want)
+				}
+			},
+		},
+		{
+			name: "add no operands", args: []string{"--json", "add"}, wantCode: exitOK,
+			check: func(t *testing.T, out string) {
+				if want := `{"operands":[],"result":0}` + "\n"; out != want {
+					t.Errorf("stdout = %q, want %q", out, want)
+				}
+			},
+		},
+		{
+			name: "add error", args: []string{"--json", "add", "x"}, wantCode: exitUsage,
+			check: func(t *testing.T, out string) {
+				if out != "" {
+					t.Errorf("stdout = %q, want empty", out)
+				}
+			},
+		},
+		{
+			name: "no command", args: []string{"--json"}, wantCode: exitUsage,
+			check: func(t *testing.T, out string) {
+				if out != "" {
+					t.Errorf("stdout = %q, want empty", out)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := newApp(strings.NewReader(tt.stdin), &out, &errOut).run(tt.args)
+			if code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, errOut.String())
+			}
+			tt.check(t, out.String())
+		})
+	}
+}
+
+func TestToolReportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	report := toolReport{statuses: []doctor.ToolStatus{{Name: "go", Path: "/usr/bin/go", Version: "go1.24", Available: true, Required: true}}}
+	if err := report.JSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"name":"go","path":"/usr/bin/go","version":"go1.24","available":true,"required":true}]` + "\n"
+	if buf.String() != want {
+		t.Errorf("JSON() = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestRunLogging(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantLog string
+	}{
+		{"default is quiet", []string{"version"}, ""},
+		{"text debug", []string{"--log-level", "debug", "version"}, "level=DEBUG msg=\"running command\" command=version"},
+		{"json debug", []string{"--log-level=DEBUG", "--log-format=json", "version"}, `"msg":"running command","command":"version"`},
+		{"info hides debug", []string{"--log-level", "info", "version"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != exitOK {
+				t.Fatalf("run(%q) = %d, want %d", tt.args, code, exitOK)
+			}
+			if tt.wantLog == "" && errOut.Len() != 0 {
+				t.Errorf("stderr = %q, want empty", errOut.String())
+			}
+			if !strings.Contains(errOut.String(), tt.wantLog) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantLog)
+			}
+			if strings.Contains(out.String(), "level=") {
+				t.Errorf("stdout = %q, logs leaked into command output", out.String())
+			}
+		})
+	}
+}
+
+func TestRunVerbosity(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `cat >/dev/null; echo pong`)
+	stub.PrependPath(t, dir)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantErrOut []string // substrings; nil means stderr must be empty
+	}{
+		{"quiet hides banner", []string{"--quiet"}, exitOK, "", nil},
+		{"quiet keeps greeting", []string{"--quiet", "greet", "--lang", "en", "--name", "Ada"}, exitOK, "Hello, Ada!\n", nil},
+		{"quiet keeps errors", []string{"--quiet", "add", "x"}, exitUsage, "", []string{`invalid integer "x"`}},
+		{"quiet and log level", []string{"--quiet", "--log-level", "warn", "version"}, exitUsage, "", []string{"--quiet cannot be combined with --log-level"}},
+		{"verbose logs paths and timing", []string{"--verbose", "ask", "ping"}, exitOK, "pong\n", []string{
+			`msg=exec bin=` + filepath.Join(dir, "claude"), "duration=", `msg="command finished" command=ask`,
+		}},
+		{"verbose and log level", []string{"--verbose", "--log-level=info", "version"}, exitUsage, "", []string{"--verbose cannot be combined with --log-level"}},
+		{"mutually exclusive", []string{"--quiet", "--verbose", "version"}, exitUsage, "", []string{"--quiet and --verbose are mutually exclusive"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d; stderr: %s", tt.args, code, tt.wantCode, errOut.String())
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if tt.wantErrOut == nil && errOut.Len() != 0 {
+				t.Errorf("stderr = %q, want empty", errOut.String())
+			}
+			for _, want := range tt.wantErrOut {
+				if !strings.Contains(errOut.String(), want) {
+					t.Errorf("stderr = %q, want it to contain %q", errOut.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunQuietSuppressesWarnings(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("PATH", dir)
+	for _, name := range []string{"go", "claude", "opencode"} {
+		stub.Write(t, dir, name, `exit 0`)
+	}
+	var out, errOut bytes.Buffer
+	a := newApp(strings.NewReader(""), &out, &errOut)
+	a.getenv = func(key string) string { return map[string]string{"MYAPP_LOG_LEVEL": "warn"}[key] }
+	if code := a.run([]string{"--quiet", "setup"}); code != exitOK {
+		t.Fatalf("setup = %d, want %d; stderr: %s", code, exitOK, errOut.String())
+	}
+	if errOut.Len() != 0 {
+		t.Errorf("stderr = %q, want the optional-tool warnings suppressed", errOut.String())
+	}
+	if !strings.Contains(out.String(), "warning") {
+		t.Errorf("stdout = %q, want the setup report itself", out.String())
+	}
+
+	out.Reset()
+	if code := a.run([]string{"--quiet", "config", "--show"}); code != exitOK || !strings.Contains(out.String(), "log_level          error  flag --quiet\n") {
+		t.Errorf("config --show = %d\n%s\nwant log_level set by --quiet", code, out.String())
+	}
+}
+
+func TestRunLogsRequestID(t *testing.T) {
+	ids := make(map[string]bool)
+	for range 2 {
+		var out, errOut bytes.Buffer
+		if code := run([]string{"--log-level", "debug", "--log-format", "json", "version"}, &out, &errOut); code != exitOK {
+			t.Fatalf("run() = %d, want %d", code, exitOK)
+		}
+		dec := json.NewDecoder(&errOut)
+		for dec.More() {
+			var record struct {
+				RequestID string `json:"request_id"`
+			}
+			if err := dec.Decode(&record); err != nil || record.RequestID == "" {
+				t.Fatalf("log record has no request_id (%v)", err)
+			}
+			ids[record.RequestID] = true
+		}
+	}
+	if len(ids) != 2 {
+		t.Errorf("request IDs = %v, want a new one per invocation", ids)
+	}
+}
+
+func TestToolReportText(t *testing.T) {
+	statuses := []doctor.ToolStatus{
+		{Name: "go", Path: "/usr/bin/go", Version: "go1.24", Available: true},
+		{Name: "opencode"},
+	}
+	tests := []struct {
+		name   string
+		color  color.Colorizer
+		format string
+		want   string
+	}{
+		{"plain", color.Colorizer{}, "", "" +
+			"TOOL      STATUS   VERSION  PATH\n" +
+			"go        ok       go1.24   /usr/bin/go\n" +
+			"opencode  missing\n"},
+		{"colored", color.Colorizer{Enabled: true}, "table", "" +
+			"TOOL      STATUS   VERSION  PATH\n" +
+			"go        \x1b[32mok\x1b[0m       go1.24   /usr/bin/go\n" +
+			"opencode  \x1b[31mmissing\x1b[0m\n"},
+		{"csv is never colored", color.Colorizer{Enabled: true}, "csv", "" +
+			"name,path,version,available\n" +
+			"go,/usr/bin/go,go1.24,true\n" +
+			"opencode,,,false\n"},
+		{"json format", color.Colorizer{}, "json", "" +
+			`[{"name":"go","path":"/usr/bin/go","version":"go1.24","available":true,"required":false},` +
+			`{"name":"opencode","path":"","version":"","available":false,"required":false}]` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := (toolReport{statuses: statuses, color: tt.color, format: tt.format}).Text(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Text() =\n%s\nwant\n%s", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDoctorInvalidFormat(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := run([]string{"doctor", "--format", "yaml"}, &out, &errOut); code != exitUsage {
+		t.Errorf("doctor --format yaml = %d, want %d", code, exitUsage)
+	}
+	if want := `invalid format "yaml"`; !strings.Contains(errOut.String(), want) {
+		t.Errorf("stderr = %q, want it to contain %q", errOut.String(), want)
+	}
+}
+
+func TestDoctorMinGo(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "go", `echo "go version go1.21.5 linux/amd64"`)
+	stub.Write(t, dir, "claude", `echo 1.0.0`)
+	stub.Write(t, dir, "opencode", `echo 0.1.0`)
+	stub.PrependPath(t, dir)
+
+	tests := []struct {
+		name     string
+		minGo    string
+		wantCode int
+		wantOut  string
+		wantErr  string
+	}{
+		{"satisfied", "1.21", exitOK, `"constraint":{"required":"1.21.0","actual":"1.21.5","satisfied":true}`, ""},
+		{"too old", "1.22", exitError, `"constraint":{"required":"1.22.0","actual":"1.21.5","satisfied":false}`, "go"},
+		{"invalid", "latest", exitUsage, "", "invalid --min-go"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			args := []string{"--json", "doctor", "--min-go", tt.minGo}
+			if code := run(args, &out, &errOut); code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", args, code, tt.wantCode, errOut.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("stdout = %s, want it to contain %s", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRunColor(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		tty       bool
+		noColor   string
+		wantColor bool
+	}{
+		{"terminal", []string{"greet"}, true, "", true},
+		{"piped", []string{"greet"}, false, "", false},
+		{"no-color flag", []string{"--no-color", "greet"}, true, "", false},
+		{"NO_COLOR env", []string{"greet"}, true, "1", false},
+		{"json never colored", []string{"--json", "greet"}, true, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", tt.noColor)
+			var out, errOut bytes.Buffer
+			a := newApp(strings.NewReader(""), &out, &errOut)
+			a.isTerminal = func(io.Writer) bool { return tt.tty }
+			if code := a.run(tt.args); code != exitOK {
+				t.Fatalf("run(%q) = %d (stderr: %s)", tt.args, code, errOut.String())
+			}
+			if got := strings.Contains(out.String(), "\x1b["); got != tt.wantColor {
+				t.Errorf("stdout = %q, colored = %v, want %v", out.String(), got, tt.wantColor)
+			}
+		})
+	}
+}
+
+func TestRunPropagatesToolExitCode(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, "claude", `cat >/dev/null; echo "quota exceeded" >&2; exit 42`)
+	stub.PrependPath(t, dir)
+
+	var out, errOut bytes.Buffer
+	if code := run([]string{"ask", "--no-cache", "hi"}, &out, &errOut); code != 42 {
+		t.Errorf("run(ask) = %d, want the tool's exit code 42", code)
+	}
+	if want := "ask: claude: exit status 42: quota exceeded\n"; !strings.HasSuffix(errOut.String(), want) {
+		t.Errorf("stderr = %q, want it to end with %q", errOut.String(), want)
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		tool       string
+		args       []string
+		wantErrOut string
+	}{
+		{"ask", "claude", []string{"ask", "--no-cache", "hi"}, "ask: claude: timed out after 200ms (see --timeout)\n"},
+		{"doctor", "go", []string{"doctor"}, "doctor: go version check: timed out after 200ms (see --timeout)\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			pidFile := filepath.Join(dir, "pid")
+			// The sleep is a child of the stub, so it only dies if the
+			// whole process group is killed.
+			stub.Write(t, dir, tt.tool, `sleep 30 & echo $! > "`+pidFile+`"; wait`)
+			stub.PrependPath(t, dir)
+
+			var out, errOut bytes.Buffer
+			args := append([]string{"--timeout", "200ms"}, tt.args...)
+			start := time.Now()
+			if code := run(args, &out, &errOut); code != exitTimeout {
+				t.Errorf("run(%q) = %d, want %d; stderr: %s", args, code, exitTimeout, errOut.String())
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("run(%q) took %v, want it bounded by --timeout", args, elapsed)
+			}
+			if !strings.HasSuffix(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr = %q, want it to end with %q", errOut.String(), tt.wantErrOut)
+			}
+			if pid := stub.WaitPID(t, pidFile); !stub.WaitExit(pid) {
+				t.Errorf("%s's child %d still running after the timeout", tt.tool, pid)
+			}
+		})
+	}
+}
+
+func TestRunInvalidTimeout(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := run([]string{"--timeout", "-1s", "ask", "hi"}, &out, &errOut); code != exitUsage {
+		t.Errorf("run(--timeout -1s) = %d, want %d", code, exitUsage)
+	}
+	if !strings.Contains(errOut.String(), "timeout from flag --timeout: must not be negative") {
+		t.Errorf("stderr = %q, want the negative timeout reported", errOut.String())
+	}
+}
+
+func TestRunOutput(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "nested", "sum.txt")
+
+	var out, errOut bytes.Buffer
+	if code := run([]string{"--output", path, "add", "2", "3"}, &out, &errOut); code != exitOK {
+		t.Fatalf("run(--output) = %d, want %d; stderr: %s", code, exitOK, errOut.String())
+	}
+	if out.Len() != 0 {
+		t.Errorf("stdout = %q, want output only in the file", out.String())
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "5\n" {
+		t.Errorf("file = %q, %v; want %q", data, err, "5\n")
+	}
+	// The temporary file was renamed into place, not copied.
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "sum.txt" {
+		t.Errorf("output directory holds %v, want only sum.txt", entries)
+	}
+
+	// A failing command reports to stderr and leaves the file alone.
+	out.Reset()
+	errOut.Reset()
+	if code := run([]string{"--output", path, "add", "x"}, &out, &errOut); code != exitUsage {
+		t.Errorf("run(--output, add x) = %d, want %d", code, exitUsage)
+	}
+	if !strings.Contains(errOut.String(), `invalid integer "x"`) {
+		t.Errorf("stderr = %q, want the error", errOut.String())
+	}
+	if data, _ := os.ReadFile(path); string(data) != "5\n" {
+		t.Errorf("file after a failure = %q, want it unchanged", data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("output directory holds %v after a failure, want only sum.txt", entries)
+	}
+
+	out.Reset()
+	if code := run([]string{"--output", "-", "add", "2", "3"}, &out, &errOut); code != exitOK || out.String() != "5\n" {
+		t.Errorf("run(--output -) = %d with stdout %q, want %d and 5", code, out.String(), exitOK)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	bins := map[string]string{}
+	for _, name := range []string{"go", "claude", "opencode"} {
+		bins[name] = stub.Write(t, dir, name, `touch "`+marker+`"`)
+	}
+	stub.PrependPath(t, dir)
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantErrOut string
+	}{
+		{"ask", []string{"--dry-run", "ask", "hi", "there"}, "printf '%s' 'hi there' | " + bins["claude"] + " --print\n"},
+		{"bench", []string{"--dry-run", "bench", "--iterations", "1"},
+			"printf '%s' 'Reply with the single word: ok' | " + bins["claude"] + " --print\n" +
+				bins["opencode"] + " run 'Reply with the single word: ok'\n"},
+		{"doctor", []string{"--dry-run", "doctor"},
+			bins["claude"] + " --version\n" + bins["go"] + " version\n" + bins["opencode"] + " --version\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != exitOK {
+				t.Fatalf("run(%q) = %d, want %d; stderr: %s", tt.args, code, exitOK, errOut.String())
+			}
+			if errOut.String() != tt.wantErrOut {
+				t.Errorf("stderr = %q, want %q", errOut.String(), tt.wantErrOut)
+			}
+			if _, err := os.Stat(marker); err == nil {
+				t.Error("a tool was executed in dry-run mode")
+			}
+		})
+	}
+}
+
+func TestCommandMetrics(t *testing.T) {
+	var out, errOut bytes.Buffer
+	a := newApp(strings.NewReader("Ann\nBen\n"), &out, &errOut)
+	greetings := a.metrics.Counter(metrics.GreetingsTotal, "")
+	adds := a.metrics.Counter(metrics.AddOperationsTotal, "")
+
+	for _, args := range [][]string{
+		{"greet"},
+		{"greet", "--stdin"},
+		{"add", "1", "2"},
+		{"add", "x"}, // rejected, so not counted
+	} {
+		a.run(args)
+	}
+
+	if n := greetings.Value(); n != 3 {
+		t.Errorf("greetings = %d, want 3", n)
+	}
+	if n := adds.Value(); n != 1 {
+		t.Errorf("add operations = %d, want 1", n)
+	}
+}
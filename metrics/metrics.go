@@ -0,0 +1,228 @@
+// Package metrics is a minimal registry of counters and histograms that
+// can be exposed in the Prometheus text format.
+//
+// All methods are safe for concurrent use, and a nil *Registry, *Counter
+// or *Histogram silently discards everything, so instrumented code does
+// not need to check whether metrics are enabled.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Registry holds metrics by name and label set.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// family is every series of one metric name.
+type family struct {
+	help   string
+	kind   string // "counter" or "histogram"
+	series map[string]any
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Counter returns the counter called name with the given labels, creating
+// it on first use. labels alternate between names and values, as in
+// "tool", "claude". help is recorded only when the name is first seen.
+// Counter panics if name is already registered as a histogram.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	if r == nil {
+		return nil
+	}
+	return r.series(name, help, "counter", labels, func() any { return new(Counter) }).(*Counter)
+}
+
+// Histogram returns the histogram called name with the given labels and
+// bucket upper bounds, creating it on first use. The buckets of an
+// existing histogram are not changed. Histogram panics if name is already
+// registered as a counter.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if r == nil {
+		return nil
+	}
+	return r.series(name, help, "histogram", labels, func() any { return newHistogram(buckets) }).(*Histogram)
+}
+
+func (r *Registry) series(name, help, kind string, labels []string, create func() any) any {
+	key := labelString(labels)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{help: help, kind: kind, series: make(map[string]any)}
+		r.families[name] = f
+	}
+	if f.kind != kind {
+		panic(fmt.Sprintf("metrics: %s registered as a %s, not a %s", name, f.kind, kind))
+	}
+	s, ok := f.series[key]
+	if !ok {
+		s = create()
+		f.series[key] = s
+	}
+	return s
+}
+
+// WriteText writes every metric in the Prometheus text exposition format,
+// sorted by name and then by labels.
+func (r *Registry) WriteText(w io.Writer) error {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(r.families)) {
+		f := r.families[name]
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(f.help), name, f.kind)
+		for _, labels := range slices.Sorted(maps.Keys(f.series)) {
+			switch s := f.series[labels].(type) {
+			case *Counter:
+				fmt.Fprintf(&b, "%s%s %d\n", name, braces(labels), s.Value())
+			case *Histogram:
+				s.write(&b, name, labels)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Handler returns an http.Handler serving WriteText.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteText(w)
+	})
+}
+
+// Counter is a monotonically increasing count.
+type Counter struct {
+	v atomic.Uint64
+}
+
+// Inc adds one to c.
+func (c *Counter) Inc() { c.Add(1) }
+
+// Add adds n to c.
+func (c *Counter) Add(n uint64) {
+	if c != nil {
+		c.v.Add(n)
+	}
+}
+
+// Value returns the current count.
+func (c *Counter) Value() uint64 {
+	if c == nil {
+		return 0
+	}
+	return c.v.Load()
+}
+
+// Histogram counts observations in cumulative buckets.
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64 // sorted upper bounds, excluding +Inf
+	counts  []uint64  // observations per bucket, not cumulative
+	count   uint64
+	sum     float64
+}
+
+func newHistogram(buckets []float64) *Histogram {
+	b := slices.Clone(buckets)
+	slices.Sort(b)
+	b = slices.Compact(b)
+	return &Histogram{buckets: b, counts: make([]uint64, len(b))}
+}
+
+// Observe records v.
+func (h *Histogram) Observe(v float64) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+		h.counts[i]++
+	}
+	h.count++
+	h.sum += v
+}
+
+// Count returns the number of observations.
+func (h *Histogram) Count() uint64 {
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.count
+}
+
+func (h *Histogram) write(b *strings.Builder, name, labels string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	withLE := func(le string) string {
+		if labels == "" {
+			return braces(`le="` + le + `"`)
+		}
+		return braces(labels + `,le="` + le + `"`)
+	}
+	var cumulative uint64
+	for i, upper := range h.buckets {
+		cumulative += h.counts[i]
+		fmt.Fprintf(b, "%s_bucket%s %d\n", name, withLE(formatFloat(upper)), cumulative)
+	}
+	fmt.Fprintf(b, "%s_bucket%s %d\n", name, withLE("+Inf"), h.count)
+	fmt.Fprintf(b, "%s_sum%s %s\n", name, braces(labels), formatFloat(h.sum))
+	fmt.Fprintf(b, "%s_count%s %d\n", name, braces(labels), h.count)
+}
+
+// labelString renders name/value pairs as name="value",... in the order
+// given. A trailing name without a value is ignored.
+func labelString(labels []string) string {
+	var parts []string
+	for i := 0; i+1 < len(labels); i += 2 {
+		parts = append(parts, labels[i]+`="`+escapeLabel(labels[i+1])+`"`)
+	}
+	return strings.Join(parts, ",")
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
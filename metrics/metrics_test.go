@@ -0,0 +1,127 @@
+package metrics
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteText(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("b_total", "B things.", "tool", "z").Add(2)
+	r.Counter("b_total", "ignored help", "tool", "a").Inc()
+	r.Counter("a_total", "A things\nwith a newline.").Inc()
+	h := r.Histogram("latency_seconds", "Latency.", []float64{1, 0.5, 1}, "tool", `say "hi"`)
+	for _, v := range []float64{0.2, 0.5, 0.7, 3} {
+		h.Observe(v)
+	}
+
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `# HELP a_total A things\nwith a newline.
+# TYPE a_total counter
+a_total 1
+# HELP b_total B things.
+# TYPE b_total counter
+b_total{tool="a"} 1
+b_total{tool="z"} 2
+# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{tool="say \"hi\"",le="0.5"} 2
+latency_seconds_bucket{tool="say \"hi\"",le="1"} 3
+latency_seconds_bucket{tool="say \"hi\"",le="+Inf"} 4
+latency_seconds_sum{tool="say \"hi\""} 4.4
+latency_seconds_count{tool="say \"hi\""} 4
+`
+	if b.String() != want {
+		t.Errorf("WriteText() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestSameSeriesIsShared(t *testing.T) {
+	r := NewRegistry()
+	c := r.Counter("x_total", "X.", "k", "v")
+	if r.Counter("x_total", "X.", "k", "v") != c {
+		t.Error("Counter() returned a new counter for an existing series")
+	}
+	if r.Counter("x_total", "X.", "k", "other") == c {
+		t.Error("Counter() shared a counter between label values")
+	}
+}
+
+func TestKindConflictPanics(t *testing.T) {
+	r := NewRegistry()
+	r.Counter("x", "X.")
+	defer func() {
+		if recover() == nil {
+			t.Error("Histogram() on a counter name did not panic")
+		}
+	}()
+	r.Histogram("x", "X.", nil)
+}
+
+func TestNilIsNoOp(t *testing.T) {
+	var r *Registry
+	r.Counter("x", "X.").Inc()
+	r.Histogram("y", "Y.", []float64{1}).Observe(2)
+	r.ObserveInvocation("claude", time.Second, errors.New("boom"))
+	r.IncGreetings(3)
+	if err := r.WriteText(&strings.Builder{}); err != nil {
+		t.Errorf("WriteText() on nil registry = %v", err)
+	}
+	if v := r.Counter("x", "X.").Value(); v != 0 {
+		t.Errorf("nil counter Value() = %d, want 0", v)
+	}
+}
+
+func TestConcurrentUse(t *testing.T) {
+	r := NewRegistry()
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 1000 {
+				r.IncGreetings(1)
+				r.ObserveInvocation("claude", time.Millisecond, nil)
+				r.WriteText(&strings.Builder{})
+			}
+		}()
+	}
+	wg.Wait()
+	if v := r.Counter(GreetingsTotal, "").Value(); v != 8000 {
+		t.Errorf("greetings = %d, want 8000", v)
+	}
+	if n := r.Histogram(ToolDurationSeconds, "", ToolBuckets, "tool", "claude").Count(); n != 8000 {
+		t.Errorf("latency observations = %d, want 8000", n)
+	}
+}
+
+func TestObserveInvocation(t *testing.T) {
+	r := NewRegistry()
+	r.ObserveInvocation("opencode", 300*time.Millisecond, nil)
+	r.ObserveInvocation("opencode", 2*time.Second, errors.New("exit status 1"))
+
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Content-Type = %q, want text/plain", ct)
+	}
+	body := rec.Body.String()
+	for _, line := range []string{
+		`myapp_tool_invocations_total{tool="opencode"} 2`,
+		`myapp_tool_errors_total{tool="opencode"} 1`,
+		`myapp_tool_duration_seconds_bucket{tool="opencode",le="0.5"} 1`,
+		`myapp_tool_duration_seconds_bucket{tool="opencode",le="2.5"} 2`,
+		`myapp_tool_duration_seconds_count{tool="opencode"} 2`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Errorf("metrics output is missing %q:\n%s", line, body)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package metrics
+
+import "time"
+
+// Names of the metrics recorded by this module's commands, server and
+// exec clients.
+const (
+	GreetingsTotal       = "myapp_greetings_total"
+	AddOperationsTotal   = "myapp_add_operations_total"
+	ToolInvocationsTotal = "myapp_tool_invocations_total"
+	ToolErrorsTotal      = "myapp_tool_errors_total"
+	ToolDurationSeconds  = "myapp_tool_duration_seconds"
+)
+
+// ToolBuckets are the histogram bounds, in seconds, for tool invocation
+// latency. AI tools routinely take tens of seconds to answer.
+var ToolBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120}
+
+// IncGreetings counts n greetings served.
+func (r *Registry) IncGreetings(n int) {
+	r.Counter(GreetingsTotal, "Greetings served.").Add(uint64(n))
+}
+
+// IncAddOperations counts one addition.
+func (r *Registry) IncAddOperations() {
+	r.Counter(AddOperationsTotal, "Add operations performed.").Inc()
+}
+
+// ObserveInvocation records one run of the external tool called tool that
+// took d and failed if err is non-nil.
+func (r *Registry) ObserveInvocation(tool string, d time.Duration, err error) {
+	r.Counter(ToolInvocationsTotal, "Invocations of external tools.", "tool", tool).Inc()
+	if err != nil {
+		r.Counter(ToolErrorsTotal, "Failed invocations of external tools.", "tool", tool).Inc()
+	}
+	r.Histogram(ToolDurationSeconds, "Latency of external tool invocations.", ToolBuckets, "tool", tool).Observe(d.Seconds())
+}
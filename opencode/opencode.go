@@ -0,0 +1,168 @@
+// Package opencode runs the OpenCode CLI as a subprocess for scripted
+// coding workflows.
+package opencode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/exitcode"
+	"github.com/ericbfriday/claude-go-containers/internal/argv"
+	"github.com/ericbfriday/claude-go-containers/internal/procgroup"
+	"github.com/ericbfriday/claude-go-containers/internal/shellquote"
+	"github.com/ericbfriday/claude-go-containers/metrics"
+)
+
+// DefaultBin is the executable looked up on PATH when no binary is given.
+const DefaultBin = "opencode"
+
+// Result is the outcome of a finished OpenCode invocation.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// Err returns an *exitcode.ExitError carrying the exit status and standard
+// error output if the invocation exited non-zero, and nil otherwise.
+func (r *Result) Err() error {
+	if r.ExitCode == 0 {
+		return nil
+	}
+	return &exitcode.ExitError{Tool: "opencode", Code: r.ExitCode, Stderr: strings.TrimSpace(r.Stderr)}
+}
+
+// Option configures a Runner.
+type Option func(*Runner)
+
+// WithBinPath makes the Runner execute path instead of looking up
+// DefaultBin on PATH.
+func WithBinPath(path string) Option {
+	return func(r *Runner) { r.binPath = path }
+}
+
+// WithLogger makes the Runner log every invocation at debug level and
+// every failure at error level.
+func WithLogger(l *slog.Logger) Option {
+	return func(r *Runner) {
+		if l != nil {
+			r.logger = l
+		}
+	}
+}
+
+// WithDryRun makes the Runner write the shell command line of every
+// invocation to w instead of executing it.
+func WithDryRun(w io.Writer) Option {
+	return func(r *Runner) { r.dryRun = w }
+}
+
+// WithMetrics makes the Runner record every invocation, its latency and
+// whether it failed or exited non-zero in reg, under the tool label
+// "opencode".
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(r *Runner) { r.metrics = reg }
+}
+
+// Runner executes OpenCode with a fixed binary.
+type Runner struct {
+	binPath string
+	logger  *slog.Logger
+	dryRun  io.Writer
+	metrics *metrics.Registry
+}
+
+// New returns a Runner. Unless WithBinPath is given, the binary is
+// resolved with exec.LookPath and an error is returned if it is missing.
+// In dry-run mode a missing binary is not an error; the command line then
+// names DefaultBin as is.
+func New(opts ...Option) (*Runner, error) {
+	r := &Runner{logger: slog.New(slog.DiscardHandler)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.binPath == "" {
+		path, err := exec.LookPath(DefaultBin)
+		if err != nil && r.dryRun != nil {
+			path, err = DefaultBin, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("opencode: %w", err)
+		}
+		r.binPath = path
+	}
+	return r, nil
+}
+
+// Exec runs OpenCode with the default Runner.
+func Exec(ctx context.Context, args ...string) (*Result, error) {
+	r, err := New()
+	if err != nil {
+		return nil, err
+	}
+	return r.Exec(ctx, args...)
+}
+
+// Exec runs the binary with args and waits for it to exit.
+//
+// A non-zero exit status is not an error: it is reported in
+// Result.ExitCode alongside the captured output. An error is returned if
+// the process cannot be started or if ctx is done first, in which case the
+// whole process group is sent SIGTERM and, after procgroup.DefaultGrace,
+// SIGKILL, so no child processes are left behind, and the error wraps
+// context.Cause(ctx).
+//
+// Each of args reaches OpenCode as one argument, exactly as given; no
+// shell is involved. An argument containing a NUL byte, which no process
+// can receive, is reported as an error before anything is run.
+//
+// In dry-run mode Exec writes the command line instead and returns an
+// empty Result.
+func (r *Runner) Exec(ctx context.Context, args ...string) (*Result, error) {
+	cmdline, err := argv.New(r.binPath, args...).Build()
+	if err != nil {
+		return nil, fmt.Errorf("opencode: %w", err)
+	}
+	if r.dryRun != nil {
+		line := shellquote.Join(cmdline...)
+		r.logger.InfoContext(ctx, "dry run", "command", line)
+		if _, err := fmt.Fprintln(r.dryRun, line); err != nil {
+			return nil, fmt.Errorf("opencode: %w", err)
+		}
+		return &Result{}, nil
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	procgroup.Configure(cmd, procgroup.DefaultGrace)
+
+	start := time.Now()
+	err = cmd.Run()
+	elapsed := time.Since(start)
+	r.metrics.ObserveInvocation("opencode", elapsed, err)
+	r.logger.DebugContext(ctx, "exec", "bin", r.binPath, "args", args, "duration", elapsed)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "opencode invocation failed", "bin", r.binPath, "args", args, "err", err)
+	}
+	res := &Result{Stdout: stdout.String(), Stderr: stderr.String(), ExitCode: cmd.ProcessState.ExitCode()}
+	if err != nil {
+		if ctx.Err() != nil {
+			return res, fmt.Errorf("opencode: %w", context.Cause(ctx))
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return res, nil
+		}
+		return nil, fmt.Errorf("opencode: %w", err)
+	}
+	return res, nil
+}
@@ -0,0 +1,212 @@
+package opencode
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/exitcode"
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+	"github.com/ericbfriday/claude-go-containers/metrics"
+)
+
+func TestExec(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name   string
+		script string
+		args   []string
+		want   Result
+	}{
+		{"stdout and args", `echo "run $*"`, []string{"run", "--quiet"}, Result{Stdout: "run run --quiet\n"}},
+		{"stderr", `echo oops >&2`, nil, Result{Stderr: "oops\n"}},
+		{"non-zero exit", `echo partial; exit 7`, nil, Result{Stdout: "partial\n", ExitCode: 7}},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bin := stub.Write(t, dir, "opencode"+string(rune('a'+i)), tt.script)
+			r, err := New(WithBinPath(bin))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := r.Exec(context.Background(), tt.args...)
+			if err != nil {
+				t.Fatalf("Exec() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("Exec() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultErr(t *testing.T) {
+	if err := (&Result{Stdout: "ok"}).Err(); err != nil {
+		t.Errorf("Err() = %v for a zero exit status, want nil", err)
+	}
+	err := (&Result{Stderr: "bad flag\n", ExitCode: 42}).Err()
+	var exitErr *exitcode.ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 42 || exitErr.Stderr != "bad flag" {
+		t.Errorf("Err() = %#v, want an ExitError with code 42 and the stderr", err)
+	}
+}
+
+func TestExecLooksUpPath(t *testing.T) {
+	dir := t.TempDir()
+	stub.Write(t, dir, DefaultBin, `echo from-path`)
+	stub.PrependPath(t, dir)
+
+	got, err := Exec(context.Background())
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if got.Stdout != "from-path\n" {
+		t.Errorf("Stdout = %q, want %q", got.Stdout, "from-path\n")
+	}
+}
+
+func TestNewMissingBinary(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	if _, err := New(); err == nil {
+		t.Fatal("New() error = nil, want error for missing binary")
+	}
+}
+
+func TestExecDryRun(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	bin := stub.Write(t, dir, DefaultBin, `touch "`+marker+`"`)
+	stub.PrependPath(t, dir)
+
+	var out bytes.Buffer
+	r, err := New(WithDryRun(&out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := r.Exec(context.Background(), "run", "fix the bug's cause")
+	if err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+	if want := bin + ` run 'fix the bug'\''s cause'` + "\n"; out.String() != want {
+		t.Errorf("dry-run output = %q, want %q", out.String(), want)
+	}
+	if *got != (Result{}) {
+		t.Errorf("Exec() = %+v, want an empty Result", *got)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("binary was executed in dry-run mode")
+	}
+}
+
+func TestNewDryRunMissingBinary(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	var out bytes.Buffer
+	r, err := New(WithDryRun(&out))
+	if err != nil {
+		t.Fatalf("New() error = %v, want none in dry-run mode", err)
+	}
+	if _, err := r.Exec(context.Background(), "--version"); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "opencode --version\n" {
+		t.Errorf("dry-run output = %q, want %q", out.String(), "opencode --version\n")
+	}
+}
+
+func TestExecMetrics(t *testing.T) {
+	dir := t.TempDir()
+	reg := metrics.NewRegistry()
+	for _, script := range []string{`exit 0`, `exit 3`} {
+		r, err := New(WithBinPath(stub.Write(t, dir, "opencode", script)), WithMetrics(reg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Exec(context.Background())
+	}
+
+	if n := reg.Counter(metrics.ToolInvocationsTotal, "", "tool", "opencode").Value(); n != 2 {
+		t.Errorf("invocations = %d, want 2", n)
+	}
+	if n := reg.Counter(metrics.ToolErrorsTotal, "", "tool", "opencode").Value(); n != 1 {
+		t.Errorf("errors = %d, want 1 for the non-zero exit", n)
+	}
+}
+
+func TestExecStartFailure(t *testing.T) {
+	r, err := New(WithBinPath("/nonexistent/opencode"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Exec(context.Background()); err == nil {
+		t.Fatal("Exec() error = nil, want error")
+	}
+}
+
+func TestExecArgsRoundTrip(t *testing.T) {
+	bin := stub.Write(t, t.TempDir(), "opencode", `printf '%s\n' "$@"`)
+	r, err := New(WithBinPath(bin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	args := []string{"run", `fix "the bug" in it's parser`, "$(touch pwned); *", ""}
+	res, err := r.Exec(context.Background(), args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Join(args, "\n") + "\n"; res.Stdout != want {
+		t.Errorf("arguments received = %q, want %q", res.Stdout, want)
+	}
+}
+
+func TestExecRejectsNULArgument(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	r, err := New(WithBinPath(stub.Write(t, dir, "opencode", `touch "`+marker+`"`)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Exec(context.Background(), "run", "a\x00b"); err == nil || !strings.Contains(err.Error(), "NUL") {
+		t.Fatalf("Exec() error = %v, want a NUL byte error", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("opencode was started despite an invalid argument")
+	}
+}
+
+func TestExecCanceled(t *testing.T) {
+	bin := stub.Write(t, t.TempDir(), "opencode", `exec sleep 30`)
+	r, err := New(WithBinPath(bin))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := r.Exec(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Exec() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestExecLogs(t *testing.T) {
+	var buf bytes.Buffer
+	bin := stub.Write(t, t.TempDir(), "opencode", `exit 0`)
+	r, err := New(WithBinPath(bin), WithLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Exec(context.Background(), "run", "hi"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"level=DEBUG", "msg=exec", "bin=" + bin, `args="[run hi]"`, "duration="} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("log output %q does not contain %q", buf.String(), want)
+		}
+	}
+}
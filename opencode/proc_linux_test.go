@@ -0,0 +1,35 @@
+package opencode
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestExecCancelKillsProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "child.pid")
+	bin := stub.Write(t, dir, "opencode", `sleep 30 & echo $! > "`+pidFile+`"; wait`)
+	r, err := New(WithBinPath(bin))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Exec(ctx)
+		done <- err
+	}()
+	pid := stub.WaitPID(t, pidFile)
+
+	cancel()
+	if err := <-done; err == nil {
+		t.Fatal("Exec() error = nil after cancellation")
+	}
+	if !stub.WaitExit(pid) {
+		t.Errorf("child process %d still running after cancellation", pid)
+	}
+}
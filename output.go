@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ericbfriday/claude-go-containers/color"
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/internal/table"
+)
+
+// Output is the result of a command. Keeping rendering here lets command
+// logic stay independent of the --json flag.
+type Output interface {
+	// Text writes the human-readable form of the result.
+	Text(w io.Writer) error
+	// JSON writes the result as JSON.
+	JSON(w io.Writer) error
+}
+
+func writeJSON(w io.Writer, v any) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// sumResult is the result of adding operands.
+type sumResult struct {
+	Operands []int `json:"operands"`
+	Result   int   `json:"result"`
+}
+
+func (r sumResult) Text(w io.Writer) error {
+	_, err := fmt.Fprintln(w, r.Result)
+	return err
+}
+
+func (r sumResult) JSON(w io.Writer) error { return writeJSON(w, r) }
+
+// toolReport is the result of the doctor check.
+type toolReport struct {
+	statuses []doctor.ToolStatus
+	color    color.Colorizer
+	format   string // one of doctor.Formats; empty means table
+}
+
+// Text renders the statuses in the report's format. Tables color each
+// tool's status green when it is available and red otherwise.
+func (r toolReport) Text(w io.Writer) error {
+	if r.format != "" && r.format != "table" {
+		return doctor.FormatToolStatus(w, r.statuses, r.format)
+	}
+	return table.Write(w, doctor.TableRows(r.statuses), func(row, col int, cell string) string {
+		if col != 1 || row == 0 {
+			return cell
+		}
+		if r.statuses[row-1].Available {
+			return r.color.Green(cell)
+		}
+		return r.color.Red(cell)
+	})
+}
+
+func (r toolReport) JSON(w io.Writer) error {
+	return doctor.FormatToolStatus(w, r.statuses, "json")
+}
+
+// newlineWriter passes writes through to w, remembering the last byte, so
+// streamed text can be finished with a newline if it lacks one.
+type newlineWriter struct {
+	w    io.Writer
+	last byte
+}
+
+func (nw *newlineWriter) Write(p []byte) (int, error) {
+	n, err := nw.w.Write(p)
+	if n > 0 {
+		nw.last = p[n-1]
+	}
+	return n, err
+}
+
+// finish writes a newline unless nothing was written or the text already
+// ends with one.
+func (nw *newlineWriter) finish() error {
+	if nw.last == 0 || nw.last == '\n' {
+		return nil
+	}
+	nw.last = '\n'
+	_, err := io.WriteString(nw.w, "\n")
+	return err
+}
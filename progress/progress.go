@@ -0,0 +1,69 @@
+// Package progress reports how far a long-running operation has got on a
+// single, continually redrawn terminal line.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// clearLine returns the cursor to the start of the line and erases it.
+const clearLine = "\r\x1b[K"
+
+// Progress draws "[done/total]" on a terminal, rewriting the line in
+// place on every change. When its writer is not a terminal it writes
+// nothing, so redirected output and logs stay free of control sequences.
+//
+// All methods are safe for concurrent use, and a nil *Progress does
+// nothing, so callers without a terminal can pass nil.
+type Progress struct {
+	w           io.Writer
+	interactive bool
+
+	mu    sync.Mutex // guards the fields below
+	done  int
+	total int
+	drawn bool // whether the line currently shows progress
+}
+
+// New returns a Progress writing to w, normally standard error, if
+// isTerminal reports that w is a terminal. isTerminal is normally
+// color.IsTerminal; tests substitute their own.
+func New(w io.Writer, isTerminal func(io.Writer) bool) *Progress {
+	return &Progress{w: w, interactive: isTerminal(w)}
+}
+
+// Update records that done of total steps are complete and redraws the
+// line if that changed it. Workers finishing out of order may report a
+// smaller done than an earlier call; the count shown never goes back.
+func (p *Progress) Update(done, total int) {
+	if p == nil || !p.interactive {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if total == p.total && done <= p.done && p.drawn {
+		return
+	}
+	if total != p.total {
+		p.done = 0
+	}
+	p.done, p.total = max(p.done, done), total
+	fmt.Fprintf(p.w, "%s[%d/%d]", clearLine, p.done, p.total)
+	p.drawn = true
+}
+
+// Finish erases the progress line so that whatever is written next starts
+// on a clean line. Update may be called again afterwards to start over.
+func (p *Progress) Finish() {
+	if p == nil || !p.interactive {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.drawn {
+		io.WriteString(p.w, clearLine)
+	}
+	p.done, p.total, p.drawn = 0, 0, false
+}
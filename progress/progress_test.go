@@ -0,0 +1,73 @@
+package progress
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func terminal(tty bool) func(io.Writer) bool {
+	return func(io.Writer) bool { return tty }
+}
+
+func TestProgressInteractive(t *testing.T) {
+	var out strings.Builder
+	p := New(&out, terminal(true))
+	p.Update(0, 3)
+	p.Update(1, 3)
+	p.Update(1, 3) // unchanged: not redrawn
+	p.Update(0, 3) // late report from a slower worker: not redrawn
+	p.Update(3, 3)
+	p.Finish()
+
+	want := clearLine + "[0/3]" + clearLine + "[1/3]" + clearLine + "[3/3]" + clearLine
+	if out.String() != want {
+		t.Errorf("wrote %q, want %q", out.String(), want)
+	}
+
+	out.Reset()
+	p.Finish()
+	if out.Len() != 0 {
+		t.Errorf("Finish() with nothing drawn wrote %q", out.String())
+	}
+	p.Update(1, 2)
+	if want := clearLine + "[1/2]"; out.String() != want {
+		t.Errorf("Update() after Finish wrote %q, want %q", out.String(), want)
+	}
+}
+
+func TestProgressQuiet(t *testing.T) {
+	var out strings.Builder
+	p := New(&out, terminal(false))
+	for i := range 5 {
+		p.Update(i, 5)
+	}
+	p.Finish()
+	if out.Len() != 0 {
+		t.Errorf("wrote %q to a non-terminal, want nothing", out.String())
+	}
+}
+
+func TestProgressNil(t *testing.T) {
+	var p *Progress
+	p.Update(1, 2)
+	p.Finish()
+}
+
+func TestProgressConcurrent(t *testing.T) {
+	var out strings.Builder
+	p := New(&out, terminal(true))
+	var wg sync.WaitGroup
+	for i := 1; i <= 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.Update(i, 100)
+		}()
+	}
+	wg.Wait()
+	if !strings.HasSuffix(out.String(), "[100/100]") {
+		t.Errorf("last line drawn = %q, want [100/100]", out.String()[strings.LastIndex(out.String(), "\r"):])
+	}
+}
@@ -0,0 +1,134 @@
+// Package prompt assembles prompts for the Claude CLI from a system
+// instruction, the contents of context files and the request itself.
+package prompt
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// DefaultMaxLength is the limit, in bytes, used when Builder.MaxLength is
+// zero.
+const DefaultMaxLength = 200_000
+
+// LengthError reports a prompt longer than the Builder allows.
+type LengthError struct {
+	Length int      // length of the full prompt, in bytes
+	Max    int      // the limit it exceeded
+	Files  []string // context files from the first one past the limit on
+}
+
+func (e *LengthError) Error() string {
+	msg := fmt.Sprintf("prompt: %d bytes exceeds the limit of %d", e.Length, e.Max)
+	if len(e.Files) > 0 {
+		msg += "; over the limit from context files: " + strings.Join(e.Files, ", ")
+	}
+	return msg
+}
+
+// Builder assembles a prompt. Sections appear in a fixed order: the
+// system instruction, then each context file in a fenced block labeled
+// with its path, then the request text. The zero value is an empty
+// prompt limited to DefaultMaxLength.
+type Builder struct {
+	// MaxLength limits the built prompt, in bytes. Zero means
+	// DefaultMaxLength and a negative value means no limit.
+	MaxLength int
+
+	system string
+	files  []string
+	text   string
+}
+
+// WithSystem sets the instruction that opens the prompt.
+func (b *Builder) WithSystem(s string) *Builder {
+	b.system = s
+	return b
+}
+
+// WithContext adds files whose contents are embedded in the prompt. They
+// are read by Build, not here.
+func (b *Builder) WithContext(files ...string) *Builder {
+	b.files = append(b.files, files...)
+	return b
+}
+
+// WithText sets the request that closes the prompt.
+func (b *Builder) WithText(s string) *Builder {
+	b.text = s
+	return b
+}
+
+// Build reads the context files and returns the assembled prompt. It
+// returns an error naming the path if a file cannot be read, and a
+// *LengthError if the prompt is longer than MaxLength.
+func (b *Builder) Build() (string, error) {
+	system, text := strings.TrimSpace(b.system), strings.TrimSpace(b.text)
+	files := make([]string, len(b.files))
+	for i, path := range b.files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("prompt: read context file %q: %w", path, err)
+		}
+		files[i] = fence(path, string(data))
+	}
+
+	var sections []string
+	if system != "" {
+		sections = append(sections, system)
+	}
+	sections = append(sections, files...)
+	if text != "" {
+		sections = append(sections, text)
+	}
+	out := strings.Join(sections, "\n\n")
+
+	max := b.MaxLength
+	if max == 0 {
+		max = DefaultMaxLength
+	}
+	if max > 0 && len(out) > max {
+		return "", &LengthError{Length: len(out), Max: max, Files: b.overflowing(system, text, files, max)}
+	}
+	return out, nil
+}
+
+// overflowing returns the paths of the context files that push a prompt
+// with the given sections past max: the system instruction and request
+// text always fit first, then files are added in order.
+func (b *Builder) overflowing(system, text string, files []string, max int) []string {
+	n := len(system) + len(text)
+	if system != "" && text != "" {
+		n += len("\n\n")
+	}
+	var over []string
+	for i, section := range files {
+		if n > 0 {
+			n += len("\n\n")
+		}
+		n += len(section)
+		if n > max {
+			over = append(over, b.files[i])
+		}
+	}
+	return over
+}
+
+// fence returns contents in a Markdown code block labeled with path. The
+// fence is longer than any run of backticks in contents, so the block
+// cannot end early.
+func fence(path, contents string) string {
+	longest, run := 0, 0
+	for _, r := range contents {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	ticks := strings.Repeat("`", max(3, longest+1))
+	contents = strings.TrimSuffix(contents, "\n")
+	return path + ":\n" + ticks + "\n" + contents + "\n" + ticks
+}
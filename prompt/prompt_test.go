@@ -0,0 +1,117 @@
+package prompt
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, contents string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBuild(t *testing.T) {
+	dir := t.TempDir()
+	main := writeFile(t, dir, "main.go", "package main\n")
+	ticks := writeFile(t, dir, "README.md", "Run:\n```sh\ngo test\n```\n")
+
+	tests := []struct {
+		name string
+		b    *Builder
+		want string
+	}{
+		{"empty", &Builder{}, ""},
+		{"text only", new(Builder).WithText("Explain goroutines"), "Explain goroutines"},
+		{"system and text", new(Builder).WithSystem("Be brief.").WithText("Explain goroutines"),
+			"Be brief.\n\nExplain goroutines"},
+		{"context file", new(Builder).WithContext(main).WithText("Review this"),
+			main + ":\n```\npackage main\n```\n\nReview this"},
+		{"fence outlasts backticks in file", new(Builder).WithContext(ticks),
+			ticks + ":\n````\nRun:\n```sh\ngo test\n```\n````"},
+		{"all sections in order", new(Builder).WithText("Q").WithContext(main).WithSystem("S"),
+			"S\n\n" + main + ":\n```\npackage main\n```\n\nQ"},
+		{"sections are trimmed", new(Builder).WithSystem("  S\n").WithText("\nQ  "), "S\n\nQ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.b.Build()
+			if err != nil {
+				t.Fatalf("Build() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Build() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildMissingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing.txt")
+	_, err := new(Builder).WithContext(path).WithText("hi").Build()
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Build() error = %v, want %v", err, fs.ErrNotExist)
+	}
+	if err == nil || !strings.Contains(err.Error(), path) {
+		t.Errorf("Build() error = %v, want it to name %s", err, path)
+	}
+}
+
+func TestBuildMaxLength(t *testing.T) {
+	dir := t.TempDir()
+	small := writeFile(t, dir, "small.txt", "x")
+	big := writeFile(t, dir, "big.txt", strings.Repeat("y", 100))
+	after := writeFile(t, dir, "after.txt", "z")
+	withFiles := func(max int) *Builder {
+		return (&Builder{MaxLength: max}).WithSystem("S").WithContext(small, big, after).WithText("Q")
+	}
+	huge := strings.Repeat("q", DefaultMaxLength+1)
+	// "S\n\n" + small's block + "\n\nQ" fits exactly; big does not.
+	smallFits := len("S\n\n") + len(fence(small, "x")) + len("\n\nQ")
+
+	tests := []struct {
+		name      string
+		b         *Builder
+		wantErr   bool
+		wantFiles []string
+	}{
+		{"fits", withFiles(1000), false, nil},
+		{"files from the first past the limit", withFiles(smallFits), true, []string{big, after}},
+		{"text alone too long", (&Builder{MaxLength: 5}).WithText("far too long"), true, nil},
+		{"negative disables limit", (&Builder{MaxLength: -1}).WithText(huge), false, nil},
+		{"zero uses default", new(Builder).WithText(huge), true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.b.Build()
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("Build() error = %v", err)
+				}
+				return
+			}
+			var lerr *LengthError
+			if !errors.As(err, &lerr) {
+				t.Fatalf("Build() error = %v, want a *LengthError", err)
+			}
+			if !slices.Equal(lerr.Files, tt.wantFiles) {
+				t.Errorf("LengthError.Files = %q, want %q", lerr.Files, tt.wantFiles)
+			}
+			for _, f := range tt.wantFiles {
+				if !strings.Contains(err.Error(), f) {
+					t.Errorf("error %q does not name %s", err, f)
+				}
+			}
+		})
+	}
+}
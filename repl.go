@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// replPrompt is written to stderr before each line is read, so stdout
+// carries only command output.
+const replPrompt = "> "
+
+// errQuit stops the REPL loop.
+var errQuit = errors.New("quit")
+
+func replCommand() command {
+	return command{
+		name:    "repl",
+		summary: "Run commands interactively, one per line, until exit or EOF",
+		setFlags: func(fs *flag.FlagSet) handler {
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 {
+					return nil, usagef("unexpected arguments: %v", args)
+				}
+				return nil, a.repl()
+			}
+		},
+	}
+}
+
+// repl reads commands from a.in and runs each through the same registry
+// and handlers as the command line. Command failures are reported and the
+// loop continues; it ends on "exit", "quit" or end of input (Ctrl-D).
+func (a *app) repl() error {
+	fmt.Fprint(a.errOut, replPrompt)
+	err := scanLines(a.in, func(line string) error {
+		defer fmt.Fprint(a.errOut, replPrompt)
+		return a.replLine(line)
+	})
+	// Leave the terminal on a fresh line after the final prompt.
+	fmt.Fprintln(a.errOut)
+	if errors.Is(err, errQuit) {
+		return nil
+	}
+	return err
+}
+
+func (a *app) replLine(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return nil
+	}
+	name, args := fields[0], fields[1:]
+	switch name {
+	case "exit", "quit":
+		return errQuit
+	case "help":
+		fmt.Fprintln(a.errOut, "Commands:")
+		for _, cmd := range commands() {
+			if cmd.name != "repl" {
+				fmt.Fprintf(a.errOut, "  %-10s %s\n", cmd.name, cmd.summary)
+			}
+		}
+		fmt.Fprintln(a.errOut, "  exit       Leave the REPL")
+		return nil
+	case "repl":
+		fmt.Fprintln(a.errOut, "already in the REPL; type 'exit' to leave")
+		return nil
+	}
+
+	cmd, ok := lookup(name)
+	if !ok {
+		if hint := didYouMean(name); hint != "" {
+			fmt.Fprintf(a.errOut, "unknown command %q%s\n", name, hint)
+			return nil
+		}
+		fmt.Fprintf(a.errOut, "unknown command %q; type 'help' for a list of commands\n", name)
+		return nil
+	}
+	a.dispatch(cmd, replArgs(name, args))
+	return nil
+}
+
+// replArgs adapts REPL shorthand to command-line arguments: for greet,
+// trailing words after any flags form the name, so "greet --lang es Ana
+// Maria" means "greet --lang es --name 'Ana Maria'".
+func replArgs(name string, args []string) []string {
+	if name != "greet" {
+		return args
+	}
+	cmd, _ := lookup(name)
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	cmd.setFlags(fs)
+	if err := fs.Parse(args); err != nil || fs.NArg() == 0 {
+		// Let dispatch report the problem, or run as typed.
+		return args
+	}
+	n := len(args) - fs.NArg()
+	return append(args[:n:n], "--name", strings.Join(fs.Args(), " "))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestREPL(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		wantOut    string
+		wantErrOut []string
+	}{
+		{
+			name:    "greet and add until EOF",
+			script:  "greet --lang es Alice\nadd 2 3\n",
+			wantOut: "Hola, Alice!\n5\n",
+		},
+		{
+			name:    "shorthand greet",
+			script:  "greet --lang de\ngreet --lang en Ada Lovelace\nadd 1 2 3\nexit\n",
+			wantOut: "Hallo, Welt!\nHello, Ada Lovelace!\n6\n",
+		},
+		{
+			name:       "unknown command keeps going",
+			script:     "frobnicate\nadd 4 5\n",
+			wantOut:    "9\n",
+			wantErrOut: []string{`unknown command "frobnicate"; type 'help'`},
+		},
+		{
+			name:       "mistyped command gets a suggestion",
+			script:     "ad 4 5\nadd 1 1\n",
+			wantOut:    "2\n",
+			wantErrOut: []string{`unknown command "ad"; did you mean add?`},
+		},
+		{
+			name:       "command error keeps going",
+			script:     "add 1 x\nadd 1 1\n",
+			wantOut:    "2\n",
+			wantErrOut: []string{`add: invalid integer "x"`},
+		},
+		{
+			name:    "exit stops reading",
+			script:  "add 1 1\nexit\nadd 2 2\n",
+			wantOut: "2\n",
+		},
+		{
+			name:    "blank lines ignored",
+			script:  "\n   \nadd 3\nquit",
+			wantOut: "3\n",
+		},
+		{
+			name:       "help",
+			script:     "help\n",
+			wantOut:    "",
+			wantErrOut: []string{"Commands:", "greet", "exit"},
+		},
+		{
+			name:       "nested repl refused",
+			script:     "repl\nadd 7\n",
+			wantOut:    "7\n",
+			wantErrOut: []string{"already in the REPL"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := newApp(strings.NewReader(tt.script), &out, &errOut).run([]string{"repl"})
+			if code != exitOK {
+				t.Fatalf("repl = %d, want %d (stderr: %s)", code, exitOK, errOut.String())
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			for _, want := range tt.wantErrOut {
+				if !strings.Contains(errOut.String(), want) {
+					t.Errorf("stderr = %q, want it to contain %q", errOut.String(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestREPLTranscript(t *testing.T) {
+	script := "greet --lang fr Marie\nadd 2 3\nbogus\nadd -- 10 -4\n"
+	var out, errOut bytes.Buffer
+	if code := newApp(strings.NewReader(script), &out, &errOut).run([]string{"repl"}); code != exitOK {
+		t.Fatalf("repl = %d (stderr: %s)", code, errOut.String())
+	}
+
+	wantOut := "Bonjour, Marie!\n5\n6\n"
+	if out.String() != wantOut {
+		t.Errorf("stdout = %q, want %q", out.String(), wantOut)
+	}
+	wantErr := "> > > unknown command \"bogus\"; type 'help' for a list of commands\n> > \n"
+	if errOut.String() != wantErr {
+		t.Errorf("stderr = %q, want %q", errOut.String(), wantErr)
+	}
+}
+
+func TestREPLSharesEnvironmentCheck(t *testing.T) {
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	for _, tool := range []string{"go", "claude", "opencode"} {
+		stub.Write(t, dir, tool, `echo `+tool+` >> "`+runs+`"; echo "go version go1.21.5"`)
+	}
+	stub.PrependPath(t, dir)
+
+	script := "doctor --min-go 1.22\ndoctor\n"
+	var out, errOut bytes.Buffer
+	if code := newApp(strings.NewReader(script), &out, &errOut).run([]string{"repl"}); code != exitOK {
+		t.Fatalf("repl = %d (stderr: %s)", code, errOut.String())
+	}
+	data, err := os.ReadFile(runs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 3 {
+		t.Errorf("tools ran %d times over two doctor commands, want 3", got)
+	}
+	// --min-go applies to the first doctor only, not to the cached result.
+	if got := strings.Count(errOut.String(), "missing required tools: go"); got != 1 {
+		t.Errorf("stderr = %q, want go reported missing once", errOut.String())
+	}
+}
@@ -0,0 +1,98 @@
+// Package retry re-runs operations that fail transiently, backing off
+// exponentially between attempts.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// jitter returns a random duration in [0, n). Tests replace it.
+var jitter = func(n time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(n)))
+}
+
+// permanentError marks an error that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Do returns it immediately instead of trying
+// again. It returns nil if err is nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Do calls fn until it succeeds, up to attempts times in total. Before
+// retry n (counting from 1) it waits for backoff·2ⁿ⁻¹, randomized to
+// between half and all of that so concurrent callers spread out.
+//
+// Do stops early if fn returns an error marked with Permanent, returning
+// the unwrapped error, or if ctx is done, returning an error that wraps
+// both ctx.Err() and fn's last error. Otherwise it returns fn's last
+// error. attempts below 1 is treated as 1.
+func Do(ctx context.Context, attempts int, backoff time.Duration, fn func() error) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for i := range attempts {
+		if i > 0 {
+			delay := backoff << (i - 1)
+			delay = delay/2 + jitter(delay-delay/2)
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("retry: %w; last error: %w", ctx.Err(), err)
+			case <-timer.C:
+			}
+		}
+
+		if err = fn(); err == nil {
+			return nil
+		}
+		var p *permanentError
+		if errors.As(err, &p) {
+			return p.err
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("retry: %w; last error: %w", ctx.Err(), err)
+		}
+	}
+	return err
+}
+
+// Policy configures Do for callers that keep retry settings in a struct
+// field.
+type Policy struct {
+	Attempts int           // total attempts, including the first
+	Backoff  time.Duration // wait before the first retry; doubles after that
+}
+
+// Do calls the package-level Do with p's settings. A nil Policy makes a
+// single attempt.
+func (p *Policy) Do(ctx context.Context, fn func() error) error {
+	if p == nil {
+		return Do(ctx, 1, 0, fn)
+	}
+	return Do(ctx, p.Attempts, p.Backoff, fn)
+}
@@ -0,0 +1,137 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errTransient = errors.New("transient")
+
+// noJitter makes delays deterministic for the duration of the test.
+func noJitter(t *testing.T) {
+	t.Helper()
+	orig := jitter
+	jitter = func(time.Duration) time.Duration { return 0 }
+	t.Cleanup(func() { jitter = orig })
+}
+
+func TestDo(t *testing.T) {
+	noJitter(t)
+	errFatal := errors.New("fatal")
+
+	tests := []struct {
+		name      string
+		attempts  int
+		failures  int   // number of leading calls that fail
+		failWith  error // error returned by failing calls
+		wantCalls int
+		wantErr   error
+	}{
+		{"succeeds first time", 3, 0, errTransient, 1, nil},
+		{"succeeds after retries", 3, 2, errTransient, 3, nil},
+		{"exhausts attempts", 3, 5, errTransient, 3, errTransient},
+		{"zero attempts means one", 0, 5, errTransient, 1, errTransient},
+		{"permanent short-circuits", 5, 5, Permanent(errFatal), 1, errFatal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := Do(context.Background(), tt.attempts, time.Microsecond, func() error {
+				calls++
+				if calls <= tt.failures {
+					return tt.failWith
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Errorf("Do() error = %v, want %v", err, tt.wantErr)
+			}
+			if IsPermanent(err) {
+				t.Errorf("Do() returned the Permanent wrapper, want the underlying error")
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("fn called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestDoBacksOffExponentially(t *testing.T) {
+	var delays []time.Duration
+	orig := jitter
+	jitter = func(n time.Duration) time.Duration {
+		delays = append(delays, n)
+		return n - 1 // upper end of the range
+	}
+	t.Cleanup(func() { jitter = orig })
+
+	_ = Do(context.Background(), 4, 2*time.Millisecond, func() error { return errTransient })
+
+	want := []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond}
+	if len(delays) != len(want) {
+		t.Fatalf("jitter called with %v, want %v", delays, want)
+	}
+	for i := range want {
+		if delays[i] != want[i] {
+			t.Errorf("retry %d jitter range = %v, want %v", i+1, delays[i], want[i])
+		}
+	}
+}
+
+func TestDoHonorsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	start := time.Now()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	err := Do(ctx, 10, time.Hour, func() error {
+		calls++
+		return errTransient
+	})
+	if !errors.Is(err, context.Canceled) || !errors.Is(err, errTransient) {
+		t.Errorf("Do() error = %v, want it to wrap %v and %v", err, context.Canceled, errTransient)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("Do() kept waiting after cancellation")
+	}
+}
+
+func TestPermanent(t *testing.T) {
+	if Permanent(nil) != nil {
+		t.Error("Permanent(nil) != nil")
+	}
+	err := Permanent(errTransient)
+	if !IsPermanent(err) || !errors.Is(err, errTransient) || err.Error() != "transient" {
+		t.Errorf("Permanent(%v) = %v, want a permanent wrapper", errTransient, err)
+	}
+	if IsPermanent(errTransient) {
+		t.Error("IsPermanent(plain error) = true")
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	noJitter(t)
+	calls := 0
+	fail := func() error { calls++; return errTransient }
+
+	var nilPolicy *Policy
+	_ = nilPolicy.Do(context.Background(), fail)
+	if calls != 1 {
+		t.Errorf("nil Policy made %d calls, want 1", calls)
+	}
+
+	calls = 0
+	_ = (&Policy{Attempts: 3, Backoff: time.Microsecond}).Do(context.Background(), fail)
+	if calls != 3 {
+		t.Errorf("Policy{Attempts: 3} made %d calls, want 3", calls)
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/config"
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/server"
+)
+
+// shutdownTimeout bounds how long in-flight requests may take to finish
+// once the server has been asked to stop.
+const shutdownTimeout = 5 * time.Second
+
+func serveCommand() command {
+	return command{
+		name:    "serve",
+		args:    "[--addr ADDR] [--ready-cache TTL] [--metrics]",
+		summary: "Serve greet, add and health probes over HTTP; SIGHUP reloads the config",
+		setFlags: func(fs *flag.FlagSet) handler {
+			addr := fs.String("addr", ":8080", "address to listen on")
+			readyCache := fs.Duration("ready-cache", doctor.DefaultCacheTTL, "how long /readyz caches the environment check")
+			withMetrics := fs.Bool("metrics", false, "serve Prometheus metrics at /metrics")
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 {
+					return nil, usagef("unexpected arguments: %v", args)
+				}
+				ln, err := net.Listen("tcp", *addr)
+				if err != nil {
+					return nil, err
+				}
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+				greeter := server.NewGreeter(a.config.GreetingTemplate)
+				hup := make(chan os.Signal, 1)
+				signal.Notify(hup, syscall.SIGHUP)
+				defer signal.Stop(hup)
+				go a.reloadOnSignal(ctx, hup, greeter)
+				a.environment.SetTTL(*readyCache)
+				opts := []server.Option{
+					server.WithEnvironmentCheck(a.environment.Check),
+					server.WithGreeter(greeter),
+				}
+				if *withMetrics {
+					opts = append(opts, server.WithMetrics(a.metrics))
+				}
+				h := server.WithRequestID(server.LogRequests(a.logger, server.NewMux(opts...)))
+				return nil, serveHTTP(ctx, ln, h, a.logger)
+			}
+		},
+	}
+}
+
+// serveHTTP serves h on ln until ctx is done, then shuts down gracefully.
+func serveHTTP(ctx context.Context, ln net.Listener, h http.Handler, logger *slog.Logger) error {
+	srv := &http.Server{
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelError),
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+	logger.Info("listening", "addr", ln.Addr().String())
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// reloadOnSignal re-reads the configuration file each time a signal
+// arrives on sig, until ctx is done, and swaps its greeting template into
+// g. A file that fails to load is logged and the previous template is kept.
+func (a *app) reloadOnSignal(ctx context.Context, sig <-chan os.Signal, g *server.Greeter) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+		}
+		cfg, err := config.Load(a.configOptions)
+		if err != nil {
+			a.logger.Error("config reload failed; keeping previous config", "err", err)
+			continue
+		}
+		g.SetTemplate(cfg.GreetingTemplate)
+		a.logger.Info("config reloaded", "greeting_template", cfg.GreetingTemplate)
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/config"
+	"github.com/ericbfriday/claude-go-containers/server"
+)
+
+func TestServeHTTPShutsDownOnCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- serveHTTP(ctx, ln, server.NewMux(), slog.New(slog.DiscardHandler)) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/add?a=2&b=3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(body), `"result":5`) {
+		t.Errorf("body = %s, want result 5", body)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveHTTP() error = %v, want nil after graceful shutdown", err)
+		}
+	case <-time.After(shutdownTimeout + time.Second):
+		t.Fatal("serveHTTP did not return after cancellation")
+	}
+}
+
+func TestServeCommandBadAddr(t *testing.T) {
+	var out, errOut strings.Builder
+	if code := run([]string{"serve", "--addr", "not-an-address"}, &out, &errOut); code != exitError {
+		t.Errorf("run(serve) = %d, want %d (stderr: %s)", code, exitError, errOut.String())
+	}
+}
+
+func TestReloadOnSignal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(tmpl string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte("greeting_template: "+tmpl+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var logs lockedBuffer
+	a := newApp(strings.NewReader(""), io.Discard, io.Discard)
+	a.logger = slog.New(slog.NewTextHandler(&logs, nil))
+	a.configOptions = config.Options{Path: path}
+
+	g := server.NewGreeter("Hi {{.Name}}")
+	ctx, cancel := context.WithCancel(context.Background())
+	sig := make(chan os.Signal)
+	done := make(chan struct{})
+	go func() {
+		a.reloadOnSignal(ctx, sig, g)
+		close(done)
+	}()
+
+	writeConfig(`"Hey {{.Name}}"`)
+	sig <- syscall.SIGHUP
+	waitFor(t, &logs, "config reloaded", 1)
+	if got := g.Template(); got != "Hey {{.Name}}" {
+		t.Errorf("Template() = %q after reload, want the new template", got)
+	}
+
+	writeConfig(`"{{.Name"`)
+	sig <- syscall.SIGHUP
+	waitFor(t, &logs, "config reload failed", 1)
+	if got := g.Template(); got != "Hey {{.Name}}" {
+		t.Errorf("Template() = %q after a failed reload, want the previous template", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reloadOnSignal did not return after cancellation")
+	}
+}
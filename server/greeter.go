@@ -0,0 +1,48 @@
+package server
+
+import (
+	"sync/atomic"
+
+	"github.com/ericbfriday/claude-go-containers/examples"
+)
+
+// Greeter produces the greetings served by /greet. Its template can be
+// replaced while requests are being served, for example when the
+// configuration is reloaded. A nil *Greeter greets by time of day.
+type Greeter struct {
+	tmpl atomic.Pointer[string]
+}
+
+// NewGreeter returns a Greeter using tmpl, a template as accepted by
+// examples.GreetTemplate. An empty tmpl greets by time of day.
+func NewGreeter(tmpl string) *Greeter {
+	g := new(Greeter)
+	g.SetTemplate(tmpl)
+	return g
+}
+
+// SetTemplate atomically replaces the template; requests already being
+// served finish with the old one.
+func (g *Greeter) SetTemplate(tmpl string) {
+	g.tmpl.Store(&tmpl)
+}
+
+// Template returns the current template.
+func (g *Greeter) Template() string {
+	if g == nil {
+		return ""
+	}
+	if p := g.tmpl.Load(); p != nil {
+		return *p
+	}
+	return ""
+}
+
+// Greet greets name with the current template.
+func (g *Greeter) Greet(name string) (string, error) {
+	tmpl := g.Template()
+	if tmpl == "" {
+		return examples.Greet(name), nil
+	}
+	return examples.GreetTemplate(name, tmpl)
+}
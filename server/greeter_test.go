@@ -0,0 +1,89 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGreeter(t *testing.T) {
+	tests := []struct {
+		name    string
+		g       *Greeter
+		want    string
+		wantErr bool
+	}{
+		{"nil greets by time of day", nil, ", Ada!", false},
+		{"empty template greets by time of day", NewGreeter(""), ", Ada!", false},
+		{"template", NewGreeter("Hey {{.Name}}!"), "Hey Ada!", false},
+		{"template failing to render", NewGreeter("{{.Missing}}"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.g.Greet("Ada")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Greet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.HasSuffix(got, tt.want) {
+				t.Errorf("Greet() = %q, want suffix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGreetTemplateErrorIs500(t *testing.T) {
+	mux := NewMux(WithGreeter(NewGreeter("{{.Missing}}")))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/greet?name=Ada", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+// TestGreeterSwapUnderLoad swaps the template while requests are served;
+// run with -race to check the swap is safe.
+func TestGreeterSwapUnderLoad(t *testing.T) {
+	templates := []string{"A {{.Name}}", "B {{.Name}}"}
+	g := NewGreeter(templates[0])
+	mux := NewMux(WithGreeter(g))
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				rec := httptest.NewRecorder()
+				mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/greet?name=Ada", nil))
+				var body map[string]string
+				if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+					t.Error(err)
+					return
+				}
+				if got := body["greeting"]; got != "A Ada" && got != "B Ada" {
+					t.Errorf("greeting = %q, want one of the templates' output", got)
+					return
+				}
+			}
+		}()
+	}
+	for i := range 1000 {
+		g.SetTemplate(templates[i%2])
+	}
+	close(stop)
+	wg.Wait()
+
+	if got := g.Template(); got != templates[1] {
+		t.Errorf("Template() = %q, want the last one set, %q", got, templates[1])
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/doctor"
+)
+
+// startTestServer serves NewMux on an ephemeral local port and returns its
+// base URL, such as http://127.0.0.1:41234, and a function that shuts it
+// down. The environment check always passes, so /readyz never probes the
+// real tools.
+func startTestServer(t *testing.T) (baseURL string, cleanup func()) {
+	t.Helper()
+	srv := httptest.NewServer(NewMux(WithEnvironmentCheck(func() ([]doctor.ToolStatus, error) { return nil, nil })))
+	return srv.URL, srv.Close
+}
+
+func TestHTTPContract(t *testing.T) {
+	baseURL, cleanup := startTestServer(t)
+	defer cleanup()
+	client := &http.Client{Timeout: 5 * time.Second}
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		wantStatus int
+		wantType   string
+		wantBody   string // substring of the response body
+		wantAllow  string
+	}{
+		{"greet with name", http.MethodGet, "/greet?name=Ada", http.StatusOK, "application/json", `, Ada!"}`, ""},
+		{"greet without name", http.MethodGet, "/greet", http.StatusOK, "application/json", `, World!"}`, ""},
+		{"greet escaped name", http.MethodGet, "/greet?name=Ada%20Lovelace", http.StatusOK, "application/json", `, Ada Lovelace!"}`, ""},
+		{"add", http.MethodGet, "/add?a=2&b=3", http.StatusOK, "application/json", `{"result":5}`, ""},
+		{"add invalid", http.MethodGet, "/add?a=two&b=3", http.StatusBadRequest, "application/json", `{"error":"parameter \"a\": invalid integer \"two\""}`, ""},
+		{"add missing", http.MethodGet, "/add", http.StatusBadRequest, "application/json", `missing integer parameter \"a\"`, ""},
+		{"add overflow", http.MethodGet, "/add?a=9223372036854775807&b=1", http.StatusBadRequest, "application/json", `"error":"integer overflow"`, ""},
+		{"greet wrong method", http.MethodPost, "/greet", http.StatusMethodNotAllowed, "text/plain; charset=utf-8", "Method Not Allowed", "GET, HEAD"},
+		{"add wrong method", http.MethodDelete, "/add?a=1&b=2", http.StatusMethodNotAllowed, "text/plain; charset=utf-8", "Method Not Allowed", "GET, HEAD"},
+		{"unknown path", http.MethodGet, "/nope", http.StatusNotFound, "text/plain; charset=utf-8", "404 page not found", ""},
+		{"healthz", http.MethodGet, "/healthz", http.StatusOK, "application/json", `{"status":"ok"}`, ""},
+		{"readyz", http.MethodGet, "/readyz", http.StatusOK, "application/json", `{"status":"ok"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, baseURL+tt.path, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("%s %s: status = %d, want %d (body: %s)", tt.method, tt.path, resp.StatusCode, tt.wantStatus, body)
+			}
+			if ct := resp.Header.Get("Content-Type"); ct != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", ct, tt.wantType)
+			}
+			if !strings.Contains(string(body), tt.wantBody) {
+				t.Errorf("body = %q, want it to contain %q", body, tt.wantBody)
+			}
+			if allow := resp.Header.Get("Allow"); allow != tt.wantAllow {
+				t.Errorf("Allow = %q, want %q", allow, tt.wantAllow)
+			}
+			if tt.wantType == "application/json" && !json.Valid(body) {
+				t.Errorf("body is not valid JSON: %s", body)
+			}
+		})
+	}
+}
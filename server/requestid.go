@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader carries a request's ID in requests and responses.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLen bounds the length of request IDs supplied by clients.
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// NewRequestID returns a random ID of 26 base32 characters.
+func NewRequestID() string { return rand.Text() }
+
+// ContextWithRequestID returns a copy of ctx carrying id.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" if
+// there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestID gives every request an ID, stored in its context for
+// RequestIDFromContext and echoed in the X-Request-ID response header. An
+// X-Request-ID supplied by the client is kept, so a request can be traced
+// across services, as long as it is at most 128 printable ASCII characters
+// without spaces; otherwise a new ID is generated, so that what ends up in
+// logs and headers is always safe to print.
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), id)))
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := range len(id) {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// LogRequests logs every request to logger at info level once it has been
+// served, with its method, path, status and duration. Wrapped in
+// WithRequestID, and with a logger built on NewLogHandler, each record also
+// carries the request's ID.
+func LogRequests(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		logger.InfoContext(r.Context(), "request",
+			"method", r.Method, "path", r.URL.Path, "status", rec.status, "duration", time.Since(start))
+	})
+}
+
+// statusRecorder remembers the status code written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (sr *statusRecorder) WriteHeader(status int) {
+	sr.status = status
+	sr.ResponseWriter.WriteHeader(status)
+}
+
+func (sr *statusRecorder) Unwrap() http.ResponseWriter { return sr.ResponseWriter }
+
+// NewLogHandler returns a handler that adds a request_id attribute to
+// every record before passing it to h: the ID in the context of the
+// logging call if there is one, and fallback otherwise. An empty fallback
+// adds nothing to records logged without a request ID in their context.
+func NewLogHandler(h slog.Handler, fallback string) slog.Handler {
+	return &logHandler{Handler: h, fallback: fallback}
+}
+
+type logHandler struct {
+	slog.Handler
+	fallback string
+}
+
+func (h *logHandler) Handle(ctx context.Context, r slog.Record) error {
+	id := RequestIDFromContext(ctx)
+	if id == "" {
+		id = h.fallback
+	}
+	if id != "" {
+		r = r.Clone()
+		r.AddAttrs(slog.String("request_id", id))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithAttrs(attrs), fallback: h.fallback}
+}
+
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{Handler: h.Handler.WithGroup(name), fallback: h.fallback}
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/doctor"
+)
+
+func TestWithRequestID(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(&logs, nil), ""))
+	mux := NewMux(WithEnvironmentCheck(func() ([]doctor.ToolStatus, error) { return nil, nil }))
+	h := WithRequestID(LogRequests(logger, mux))
+
+	tests := []struct {
+		name     string
+		supplied string
+		keep     bool
+	}{
+		{"supplied", "trace-42.abc", true},
+		{"absent", "", false},
+		{"with spaces", "two words", false},
+		{"control characters", "id\x1b[31m", false},
+		{"too long", strings.Repeat("x", maxRequestIDLen+1), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs.Reset()
+			req := httptest.NewRequest(http.MethodGet, "/add?a=1&b=2", nil)
+			if tt.supplied != "" {
+				req.Header.Set(RequestIDHeader, tt.supplied)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			id := rec.Header().Get(RequestIDHeader)
+			if tt.keep && id != tt.supplied {
+				t.Errorf("%s = %q, want the supplied %q", RequestIDHeader, id, tt.supplied)
+			}
+			if !tt.keep && (id == "" || id == tt.supplied) {
+				t.Errorf("%s = %q, want a newly generated ID", RequestIDHeader, id)
+			}
+
+			var record map[string]any
+			if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+				t.Fatalf("log %q: %v", logs.String(), err)
+			}
+			if record["msg"] != "request" || record["request_id"] != id || record["path"] != "/add" || record["status"] != float64(http.StatusOK) {
+				t.Errorf("log record = %v, want the request logged under ID %q", record, id)
+			}
+		})
+	}
+}
+
+func TestRequestIDUnique(t *testing.T) {
+	a, b := NewRequestID(), NewRequestID()
+	if a == b || len(a) != 26 {
+		t.Errorf("NewRequestID() = %q, %q, want two distinct 26-character IDs", a, b)
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if id := RequestIDFromContext(context.Background()); id != "" {
+		t.Errorf("RequestIDFromContext(no ID) = %q, want empty", id)
+	}
+	ctx := ContextWithRequestID(context.Background(), "abc")
+	if id := RequestIDFromContext(ctx); id != "abc" {
+		t.Errorf("RequestIDFromContext() = %q, want abc", id)
+	}
+}
+
+func TestLogHandlerFallback(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(NewLogHandler(slog.NewTextHandler(&logs, nil), "cli-1")).With("k", "v")
+
+	logger.Info("no context")
+	logger.InfoContext(ContextWithRequestID(context.Background(), "req-2"), "in request")
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "k=v request_id=cli-1") || !strings.HasSuffix(lines[1], "k=v request_id=req-2") {
+		t.Errorf("logs =\n%s\nwant the fallback ID, then the context's", logs.String())
+	}
+
+	logs.Reset()
+	slog.New(NewLogHandler(slog.NewTextHandler(&logs, nil), "")).Info("plain")
+	if strings.Contains(logs.String(), "request_id") {
+		t.Errorf("log = %q, want no request_id without a fallback or context ID", logs.String())
+	}
+}
+
+func TestLogRequestsStatus(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	h := LogRequests(logger, NewMux())
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/add?a=x", nil))
+	if !strings.Contains(logs.String(), "status=400") {
+		t.Errorf("log = %q, want status=400", logs.String())
+	}
+}
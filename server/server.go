@@ -0,0 +1,151 @@
+// Package server exposes the examples package over HTTP.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/examples"
+	"github.com/ericbfriday/claude-go-containers/metrics"
+)
+
+// Option configures the handler returned by NewMux.
+type Option func(*options)
+
+type options struct {
+	check   func() ([]doctor.ToolStatus, error)
+	metrics *metrics.Registry
+	greeter *Greeter
+}
+
+// WithEnvironmentCheck sets the check behind /readyz, which is called on
+// every request and so should be cheap, such as the Check method of a
+// doctor.EnvironmentCache shared with the rest of the process. Without it
+// /readyz has a cache of its own with doctor.DefaultCacheTTL.
+func WithEnvironmentCheck(check func() ([]doctor.ToolStatus, error)) Option {
+	return func(o *options) { o.check = check }
+}
+
+// WithMetrics counts greetings and additions in reg and serves it at
+// /metrics in the Prometheus text format. Without it there is no /metrics
+// endpoint.
+func WithMetrics(reg *metrics.Registry) Option {
+	return func(o *options) { o.metrics = reg }
+}
+
+// WithGreeter makes /greet use g, so its template can be swapped while the
+// server runs. Without it /greet greets by time of day.
+func WithGreeter(g *Greeter) Option {
+	return func(o *options) { o.greeter = g }
+}
+
+// NewMux returns a handler serving:
+//
+//	GET /greet?name=Alice   {"greeting":"Hello, Alice!"}
+//	GET /add?a=2&b=3        {"result":5}
+//	GET /healthz            {"status":"ok"}
+//	GET /readyz             {"status":"ok"} or 503 {"status":"unavailable","missing":[...]}
+//	GET /metrics            Prometheus text format, only with WithMetrics
+//
+// Invalid parameters produce a 400 response with a JSON body of the form
+// {"error":"..."}; a greeting template that fails to render produces a 500
+// response of the same form.
+func NewMux(opts ...Option) *http.ServeMux {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.check == nil {
+		o.check = doctor.CachedEnvironment(doctor.DefaultCacheTTL)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /greet", handleGreet(o.greeter, o.metrics))
+	mux.HandleFunc("GET /add", handleAdd(o.metrics))
+	mux.HandleFunc("GET /healthz", handleHealthz)
+	mux.HandleFunc("GET /readyz", handleReadyz(o.check))
+	if o.metrics != nil {
+		mux.Handle("GET /metrics", o.metrics.Handler())
+	}
+	return mux
+}
+
+func handleGreet(g *Greeter, reg *metrics.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		greeting, err := g.Greet(r.URL.Query().Get("name"))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		reg.IncGreetings(1)
+		writeJSON(w, http.StatusOK, map[string]string{"greeting": greeting})
+	}
+}
+
+func handleAdd(reg *metrics.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sum, err := add(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		reg.IncAddOperations()
+		writeJSON(w, http.StatusOK, map[string]int{"result": sum})
+	}
+}
+
+// add returns the sum of the a and b query parameters.
+func add(r *http.Request) (int, error) {
+	a, errA := intParam(r, "a")
+	b, errB := intParam(r, "b")
+	if err := errors.Join(errA, errB); err != nil {
+		return 0, err
+	}
+	return examples.AddChecked(a, b)
+}
+
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadyz reports whether check finds every required tool.
+func handleReadyz(check func() ([]doctor.ToolStatus, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		statuses, err := check()
+		switch missing := doctor.Missing(statuses); {
+		case err != nil:
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable", "error": err.Error()})
+		case len(missing) > 0:
+			writeJSON(w, http.StatusServiceUnavailable, map[string]any{"status": "unavailable", "missing": missing})
+		default:
+			writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+		}
+	}
+}
+
+// intParam parses the query parameter key as an integer.
+func intParam(r *http.Request, key string) (int, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return 0, fmt.Errorf("missing integer parameter %q", key)
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("parameter %q: invalid integer %q", key, v)
+	}
+	return n, nil
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
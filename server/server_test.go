@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/metrics"
+)
+
+func TestNewMux(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		wantStatus int
+		wantKey    string
+		wantValue  any
+	}{
+		{"greet with name", http.MethodGet, "/greet?name=Alice", http.StatusOK, "greeting", ", Alice!"},
+		{"greet without name", http.MethodGet, "/greet", http.StatusOK, "greeting", ", World!"},
+		{"add", http.MethodGet, "/add?a=2&b=3", http.StatusOK, "result", float64(5)},
+		{"add negative", http.MethodGet, "/add?a=-2&b=-3", http.StatusOK, "result", float64(-5)},
+		{"add malformed", http.MethodGet, "/add?a=two&b=3", http.StatusBadRequest, "error", `invalid integer "two"`},
+		{"add missing", http.MethodGet, "/add?a=2", http.StatusBadRequest, "error", `missing integer parameter "b"`},
+		{"healthz", http.MethodGet, "/healthz", http.StatusOK, "status", "ok"},
+		{"add overflow", http.MethodGet, "/add?a=9223372036854775807&b=1", http.StatusBadRequest, "error", "overflow"},
+	}
+
+	mux := NewMux(WithEnvironmentCheck(func() ([]doctor.ToolStatus, error) { return nil, nil }))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", ct)
+			}
+			var body map[string]any
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			got := body[tt.wantKey]
+			if s, ok := tt.wantValue.(string); ok {
+				if str, _ := got.(string); !strings.Contains(str, s) {
+					t.Errorf("%s = %v, want it to contain %q", tt.wantKey, got, s)
+				}
+			} else if got != tt.wantValue {
+				t.Errorf("%s = %v, want %v", tt.wantKey, got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	var (
+		statuses []doctor.ToolStatus
+		checkErr error
+	)
+	mux := NewMux(WithEnvironmentCheck(func() ([]doctor.ToolStatus, error) {
+		return statuses, checkErr
+	}))
+	get := func() (int, map[string]any) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		var body map[string]any
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		return rec.Code, body
+	}
+
+	statuses = []doctor.ToolStatus{
+		{Name: "go", Available: true, Required: true},
+		{Name: "claude", Required: true},
+		{Name: "opencode", Required: true},
+	}
+	code, body := get()
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+	if missing, _ := body["missing"].([]any); len(missing) != 2 || missing[0] != "claude" || missing[1] != "opencode" {
+		t.Errorf("missing = %v, want [claude opencode]", body["missing"])
+	}
+
+	// The handler itself caches nothing: the check decides.
+	statuses = []doctor.ToolStatus{{Name: "go", Available: true, Required: true}}
+	if code, _ := get(); code != http.StatusOK {
+		t.Errorf("tools available: status = %d, want %d", code, http.StatusOK)
+	}
+
+	checkErr = errors.New("boom")
+	if code, body := get(); code != http.StatusServiceUnavailable || body["error"] != "boom" {
+		t.Errorf("check error: status = %d, body = %v; want 503 with error", code, body)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	mux := NewMux(WithMetrics(reg), WithEnvironmentCheck(func() ([]doctor.ToolStatus, error) { return nil, nil }))
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+	greetings := reg.Counter(metrics.GreetingsTotal, "")
+	adds := reg.Counter(metrics.AddOperationsTotal, "")
+	greetingsBefore, addsBefore := greetings.Value(), adds.Value()
+
+	get("/greet?name=Alice")
+	get("/greet")
+	get("/add?a=1&b=2")
+	get("/add?a=x&b=2") // rejected, so not counted
+	get("/healthz")
+
+	if d := greetings.Value() - greetingsBefore; d != 2 {
+		t.Errorf("greetings counter rose by %d, want 2", d)
+	}
+	if d := adds.Value() - addsBefore; d != 1 {
+		t.Errorf("add counter rose by %d, want 1", d)
+	}
+
+	rec := get("/metrics")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/metrics status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	for _, line := range []string{"myapp_greetings_total 2\n", "myapp_add_operations_total 1\n"} {
+		if !strings.Contains(rec.Body.String(), line) {
+			t.Errorf("/metrics body is missing %q:\n%s", line, rec.Body)
+		}
+	}
+}
+
+func TestMetricsDisabledByDefault(t *testing.T) {
+	mux := NewMux(WithEnvironmentCheck(func() ([]doctor.ToolStatus, error) { return nil, nil }))
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("/metrics status = %d, want %d without WithMetrics", rec.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/ericbfriday/claude-go-containers/color"
+	"github.com/ericbfriday/claude-go-containers/config"
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/internal/table"
+)
+
+func setupCommand() command {
+	return command{
+		name:    "setup",
+		args:    "[--install TOOL=COMMAND]...",
+		summary: "Verify the required tools are on PATH and print how to install any that are missing",
+		setFlags: func(fs *flag.FlagSet) handler {
+			install := make(map[string]string)
+			fs.Func("install", "print `TOOL=COMMAND` as the install command for TOOL (default $MYAPP_INSTALL_TOOL, then the built-in one)", func(s string) error {
+				tool, cmd, ok := strings.Cut(s, "=")
+				if !ok || tool == "" || cmd == "" {
+					return fmt.Errorf("want TOOL=COMMAND, got %q", s)
+				}
+				names := make([]string, len(doctor.DefaultRequirements))
+				for i, req := range doctor.DefaultRequirements {
+					names[i] = req.Name
+				}
+				if !slices.Contains(names, tool) {
+					return fmt.Errorf("unknown tool %q: want one of %s", tool, strings.Join(names, ", "))
+				}
+				install[tool] = cmd
+				return nil
+			})
+			return func(a *app, args []string) (Output, error) {
+				if len(args) > 0 {
+					return nil, usagef("unexpected arguments: %v", args)
+				}
+				overrides := make(map[string]string)
+				for _, req := range doctor.DefaultRequirements {
+					if cmd := a.getenv(installEnvVar(req.Name)); cmd != "" {
+						overrides[req.Name] = cmd
+					}
+				}
+				for tool, cmd := range install {
+					overrides[tool] = cmd
+				}
+				statuses := doctor.Verify(doctor.DefaultRequirements, overrides, exec.LookPath)
+				for _, st := range statuses {
+					if st.State() == "warning" {
+						a.logger.Warn("optional tool not found", "tool", st.Name, "install", st.Install)
+					}
+				}
+				report := setupReport{statuses: statuses, color: a.color}
+				if missing := doctor.MissingRequirements(statuses); len(missing) > 0 {
+					return report, fmt.Errorf("missing required tools: %s", strings.Join(missing, ", "))
+				}
+				return report, nil
+			}
+		},
+	}
+}
+
+// installEnvVar returns the environment variable overriding the install
+// command of tool: MYAPP_INSTALL_ and the name in upper case, with anything
+// but letters and digits replaced by underscores.
+func installEnvVar(tool string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z':
+			return r - 'a' + 'A'
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, tool)
+	return config.EnvPrefix + "INSTALL_" + name
+}
+
+// setupReport is the result of the setup command.
+type setupReport struct {
+	statuses []doctor.RequirementStatus
+	color    color.Colorizer
+}
+
+// Text writes a TOOL, STATUS, DETAIL table, where DETAIL is the path of
+// each tool found and the command installing each one that was not.
+// Statuses are green when found, yellow for missing optional tools and red
+// for missing required ones.
+func (r setupReport) Text(w io.Writer) error {
+	rows := [][]string{{"TOOL", "STATUS", "DETAIL"}}
+	for _, st := range r.statuses {
+		detail := st.Path
+		if !st.Found {
+			detail = "install with: " + st.Install
+		}
+		rows = append(rows, []string{st.Name, st.State(), detail})
+	}
+	return table.Write(w, rows, func(row, col int, cell string) string {
+		if col != 1 || row == 0 {
+			return cell
+		}
+		switch st := r.statuses[row-1]; {
+		case st.Found:
+			return r.color.Green(cell)
+		case st.Required:
+			return r.color.Red(cell)
+		default:
+			return r.color.Yellow(cell)
+		}
+	})
+}
+
+func (r setupReport) JSON(w io.Writer) error {
+	statuses := r.statuses
+	if statuses == nil {
+		statuses = []doctor.RequirementStatus{}
+	}
+	return writeJSON(w, statuses)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ericbfriday/claude-go-containers/doctor"
+	"github.com/ericbfriday/claude-go-containers/internal/stub"
+)
+
+func TestSetup(t *testing.T) {
+	dir := t.TempDir()
+	// PATH holds only the stubs, so no real tools are found.
+	t.Setenv("PATH", dir)
+	for _, name := range []string{"go", "claude", "gopls"} {
+		stub.Write(t, dir, name, `exit 0`)
+	}
+	setup := func(env map[string]string, args ...string) (code int, out, errOut string) {
+		t.Helper()
+		var o, e bytes.Buffer
+		a := newApp(strings.NewReader(""), &o, &e)
+		a.getenv = func(key string) string { return env[key] }
+		code = a.run(args)
+		return code, o.String(), e.String()
+	}
+
+	code, out, errOut := setup(nil, "setup")
+	if code != exitError {
+		t.Errorf("setup without opencode = %d, want %d", code, exitError)
+	}
+	for _, want := range []string{
+		"go           ok       " + dir + "/go\n",
+		"opencode     missing  install with: npm install -g opencode-ai@latest\n",
+		"dlv          warning  install with: go install github.com/go-delve/delve/cmd/dlv@latest\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("setup output =\n%s\nwant it to contain %q", out, want)
+		}
+	}
+	if !strings.Contains(errOut, "missing required tools: opencode") || !strings.Contains(errOut, "optional tool not found") {
+		t.Errorf("stderr = %q, want the missing required tool and warnings for optional ones", errOut)
+	}
+
+	env := map[string]string{"MYAPP_INSTALL_OPENCODE": "brew install opencode", "MYAPP_INSTALL_DLV": "apt install delve"}
+	_, out, _ = setup(env, "--json", "setup", "--install", "dlv=go install ./dlv")
+	var statuses []doctor.RequirementStatus
+	if err := json.Unmarshal([]byte(out), &statuses); err != nil {
+		t.Fatal(err)
+	}
+	install := make(map[string]string)
+	for _, st := range statuses {
+		install[st.Name] = st.Install
+	}
+	if install["opencode"] != "brew install opencode" || install["dlv"] != "go install ./dlv" {
+		t.Errorf("install commands = %v, want opencode's from the environment and dlv's from the flag", install)
+	}
+
+	// Optional tools missing only warn.
+	stub.Write(t, dir, "opencode", `exit 0`)
+	if code, _, errOut := setup(nil, "setup"); code != exitOK {
+		t.Errorf("setup with every required tool = %d, want %d; stderr: %s", code, exitOK, errOut)
+	}
+}
+
+func TestSetupUsage(t *testing.T) {
+	for _, args := range [][]string{{"setup", "extra"}, {"setup", "--install", "novalue"}, {"setup", "--install", "=x"}, {"setup", "--install", "foo=bar"}} {
+		var out, errOut bytes.Buffer
+		if code := run(args, &out, &errOut); code != exitUsage {
+			t.Errorf("run(%q) = %d, want %d", args, code, exitUsage)
+		}
+	}
+
+	var out, errOut bytes.Buffer
+	run([]string{"setup", "--install", "foo=bar"}, &out, &errOut)
+	if want := `unknown tool "foo": want one of go, claude, opencode`; !strings.Contains(errOut.String(), want) {
+		t.Errorf("stderr = %q, want it to contain %q", errOut.String(), want)
+	}
+}
+
+func TestInstallEnvVar(t *testing.T) {
+	tests := map[string]string{"go": "MYAPP_INSTALL_GO", "claude-code": "MYAPP_INSTALL_CLAUDE_CODE", "gopls2": "MYAPP_INSTALL_GOPLS2"}
+	for tool, want := range tests {
+		if got := installEnvVar(tool); got != want {
+			t.Errorf("installEnvVar(%q) = %q, want %q", tool, got, want)
+		}
+	}
+}
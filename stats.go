@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ericbfriday/claude-go-containers/internal/table"
+	"github.com/ericbfriday/claude-go-containers/stats"
+)
+
+func statsCommand() command {
+	return command{
+		name:    "stats",
+		args:    "[<n>...]",
+		summary: "Print count, sum, mean, min, max and standard deviation of numbers from args or stdin",
+		setFlags: func(fs *flag.FlagSet) handler {
+			return func(a *app, args []string) (Output, error) {
+				nums := make([]float64, 0, len(args))
+				for _, arg := range args {
+					x, err := parseNumber(arg)
+					if err != nil {
+						return nil, usagef("%v", err)
+					}
+					nums = append(nums, x)
+				}
+				if len(args) == 0 {
+					var err error
+					if nums, err = readNumbers(a.in); err != nil {
+						return nil, err
+					}
+				}
+				s, err := stats.Summarize(nums)
+				switch {
+				case errors.Is(err, stats.ErrNoSamples):
+					return nil, errors.New("no numbers to summarize")
+				case errors.Is(err, stats.ErrOverflow):
+					return nil, errors.New("summary out of the range of float64")
+				case err != nil:
+					return nil, err
+				}
+				return summaryResult(s), nil
+			}
+		},
+	}
+}
+
+// readNumbers reads whitespace-separated numbers from r.
+func readNumbers(r io.Reader) ([]float64, error) {
+	var nums []float64
+	lineNo := 0
+	err := scanLines(r, func(line string) error {
+		lineNo++
+		for _, field := range strings.Fields(line) {
+			x, err := parseNumber(field)
+			if err != nil {
+				return fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			nums = append(nums, x)
+		}
+		return nil
+	})
+	return nums, err
+}
+
+// parseNumber parses a finite decimal or integer number. NaN and the
+// infinities are rejected because they have no JSON encoding.
+func parseNumber(s string) (float64, error) {
+	x, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(x) || math.IsInf(x, 0) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return x, nil
+}
+
+// summaryResult is the result of the stats command.
+type summaryResult stats.Summary
+
+// Text writes one aligned name and value per line.
+func (r summaryResult) Text(w io.Writer) error {
+	f := func(x float64) string { return strconv.FormatFloat(x, 'g', -1, 64) }
+	return table.Write(w, [][]string{
+		{"count", strconv.Itoa(r.Count)},
+		{"sum", f(r.Sum)},
+		{"mean", f(r.Mean)},
+		{"min", f(r.Min)},
+		{"max", f(r.Max)},
+		{"stddev", f(r.StdDev)},
+	}, nil)
+}
+
+func (r summaryResult) JSON(w io.Writer) error { return writeJSON(w, stats.Summary(r)) }
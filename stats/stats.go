@@ -0,0 +1,64 @@
+// Package stats summarizes measurements such as command latencies.
+package stats
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"time"
+)
+
+// ErrNoSamples is returned when summarizing an empty set of measurements.
+var ErrNoSamples = errors.New("stats: no samples")
+
+// Latency summarizes a set of durations.
+type Latency struct {
+	Count  int           `json:"count"`
+	Min    time.Duration `json:"min_ns"`
+	Median time.Duration `json:"median_ns"`
+	P95    time.Duration `json:"p95_ns"`
+	Max    time.Duration `json:"max_ns"`
+}
+
+// Latencies returns the summary of samples, which it does not modify. It
+// returns ErrNoSamples if samples is empty.
+func Latencies(samples []time.Duration) (Latency, error) {
+	if len(samples) == 0 {
+		return Latency{}, ErrNoSamples
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	return Latency{
+		Count:  len(sorted),
+		Min:    sorted[0],
+		Median: median(sorted),
+		P95:    Percentile(sorted, 95),
+		Max:    sorted[len(sorted)-1],
+	}, nil
+}
+
+// median returns the middle of sorted, averaging the two middle values
+// when there is an even number of them.
+func median(sorted []time.Duration) time.Duration {
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	lo, hi := sorted[mid-1], sorted[mid]
+	return lo + (hi-lo)/2
+}
+
+// Percentile returns the p-th percentile of sorted, which must be in
+// ascending order, using the nearest-rank method: the smallest sample that
+// is greater than or equal to p percent of the samples. p is clamped to
+// [0, 100]; Percentile returns 0 for an empty slice.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	p = min(max(p, 0), 100)
+	// Multiplying before dividing keeps the rank exact for whole
+	// percentiles, so 95% of 20 samples is rank 19, not 20.
+	rank := int(math.Ceil(p * float64(len(sorted)) / 100))
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,81 @@
+package stats
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func ms(vals ...int) []time.Duration {
+	out := make([]time.Duration, len(vals))
+	for i, v := range vals {
+		out[i] = time.Duration(v) * time.Millisecond
+	}
+	return out
+}
+
+func TestLatencies(t *testing.T) {
+	twenty := make([]int, 20)
+	for i := range twenty {
+		twenty[i] = 20 - i // descending, to exercise sorting
+	}
+
+	tests := []struct {
+		name    string
+		samples []time.Duration
+		want    Latency
+	}{
+		{"single", ms(7), Latency{1, 7 * time.Millisecond, 7 * time.Millisecond, 7 * time.Millisecond, 7 * time.Millisecond}},
+		{"odd count", ms(30, 10, 20), Latency{3, 10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond, 30 * time.Millisecond}},
+		{"even count averages middle", ms(40, 10, 20, 30), Latency{4, 10 * time.Millisecond, 25 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond}},
+		{"p95 of twenty", ms(twenty...), Latency{20, time.Millisecond, 10500 * time.Microsecond, 19 * time.Millisecond, 20 * time.Millisecond}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orig := slices.Clone(tt.samples)
+			got, err := Latencies(tt.samples)
+			if err != nil {
+				t.Fatalf("Latencies() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Latencies() = %+v, want %+v", got, tt.want)
+			}
+			if !slices.Equal(tt.samples, orig) {
+				t.Errorf("Latencies() reordered its input to %v", tt.samples)
+			}
+		})
+	}
+}
+
+func TestLatenciesEmpty(t *testing.T) {
+	if _, err := Latencies(nil); !errors.Is(err, ErrNoSamples) {
+		t.Errorf("Latencies(nil) error = %v, want %v", err, ErrNoSamples)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := ms(1, 2, 3, 4, 5, 6, 7, 8, 9, 10)
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{0, time.Millisecond},
+		{10, time.Millisecond},
+		{11, 2 * time.Millisecond},
+		{50, 5 * time.Millisecond},
+		{95, 10 * time.Millisecond},
+		{100, 10 * time.Millisecond},
+		{-5, time.Millisecond},
+		{150, 10 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := Percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("Percentile(1..10ms, %v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Percentile(nil, 50) = %v, want 0", got)
+	}
+}
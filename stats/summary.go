@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrOverflow is returned when a statistic of finite numbers is too large
+// to represent as a float64, such as the sum of 1e308 and 1e308.
+var ErrOverflow = errors.New("stats: result out of range")
+
+// Summary describes a set of numbers. StdDev is the population standard
+// deviation.
+type Summary struct {
+	Count  int     `json:"count"`
+	Sum    float64 `json:"sum"`
+	Mean   float64 `json:"mean"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	StdDev float64 `json:"stddev"`
+}
+
+// Summarize returns the summary of nums, which it does not modify. If nums
+// is empty, Summarize returns ErrNoSamples. If any statistic overflows to
+// an infinity, or nums holds one, Summarize returns ErrOverflow rather than
+// a summary that cannot be encoded as JSON.
+//
+// The mean and standard deviation are computed in a single pass with
+// Welford's algorithm, which, unlike subtracting the squared mean from the
+// mean of squares, does not lose precision when the values are large
+// relative to their spread.
+func Summarize(nums []float64) (Summary, error) {
+	if len(nums) == 0 {
+		return Summary{}, ErrNoSamples
+	}
+	s := Summary{Min: nums[0], Max: nums[0]}
+	var m2 float64 // sum of squared differences from the running mean
+	for _, x := range nums {
+		s.Count++
+		s.Sum += x
+		s.Min = min(s.Min, x)
+		s.Max = max(s.Max, x)
+		delta := x - s.Mean
+		s.Mean += delta / float64(s.Count)
+		m2 += delta * (x - s.Mean)
+	}
+	s.StdDev = math.Sqrt(m2 / float64(s.Count))
+	for _, x := range []float64{s.Sum, s.Mean, s.Min, s.Max, s.StdDev} {
+		if math.IsInf(x, 0) || math.IsNaN(x) {
+			return Summary{}, ErrOverflow
+		}
+	}
+	return s, nil
+}
@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name string
+		nums []float64
+		want Summary
+	}{
+		{"single", []float64{4}, Summary{Count: 1, Sum: 4, Mean: 4, Min: 4, Max: 4}},
+		{"population stddev", []float64{2, 4, 4, 4, 5, 5, 7, 9}, Summary{Count: 8, Sum: 40, Mean: 5, Min: 2, Max: 9, StdDev: 2}},
+		{"negative", []float64{-1, -3}, Summary{Count: 2, Sum: -4, Mean: -2, Min: -3, Max: -1, StdDev: 1}},
+		{"fractional", []float64{0.5, 1.5}, Summary{Count: 2, Sum: 2, Mean: 1, Min: 0.5, Max: 1.5, StdDev: 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := slices.Clone(tt.nums)
+			got, err := Summarize(in)
+			if err != nil {
+				t.Fatalf("Summarize(%v) error = %v", tt.nums, err)
+			}
+			if got != tt.want {
+				t.Errorf("Summarize(%v) = %+v, want %+v", tt.nums, got, tt.want)
+			}
+			if !slices.Equal(in, tt.nums) {
+				t.Errorf("Summarize modified its input: %v", in)
+			}
+		})
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	if got, err := Summarize(nil); !errors.Is(err, ErrNoSamples) {
+		t.Errorf("Summarize(nil) = %+v, %v; want ErrNoSamples", got, err)
+	}
+}
+
+func TestSummarizeOverflow(t *testing.T) {
+	tests := []struct {
+		name string
+		nums []float64
+	}{
+		{"sum", []float64{1e308, 1e308}},
+		{"negative sum", []float64{-1e308, -1e308}},
+		{"spread", []float64{math.MaxFloat64, -math.MaxFloat64}},
+		{"infinite input", []float64{1, math.Inf(1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Summarize(tt.nums); !errors.Is(err, ErrOverflow) {
+				t.Errorf("Summarize(%v) = %+v, %v; want ErrOverflow", tt.nums, got, err)
+			}
+		})
+	}
+}
+
+// TestSummarizeLargeOffset checks the case the naive sum-of-squares
+// formula gets wrong: a small spread around a large mean, where
+// mean(x²) - mean(x)² cancels catastrophically.
+func TestSummarizeLargeOffset(t *testing.T) {
+	const offset = 1e9
+	nums := []float64{offset + 4, offset + 7, offset + 13, offset + 16}
+	got, err := Summarize(nums)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Mean != offset+10 {
+		t.Errorf("Mean = %v, want %v", got.Mean, offset+10.0)
+	}
+	// The deviations are -6, -3, 3 and 6, so the variance is 90/4.
+	if want := math.Sqrt(22.5); math.Abs(got.StdDev-want) > 1e-9 {
+		t.Errorf("StdDev = %v, want %v", got.StdDev, want)
+	}
+}
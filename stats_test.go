@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestStatsCommand(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		stdin      string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{"args", []string{"stats", "2", "4", "4", "4", "5", "5", "7", "9"}, "", exitOK,
+			"count   8\nsum     40\nmean    5\nmin     2\nmax     9\nstddev  2\n", ""},
+		{"stdin", []string{"stats"}, "1.5 2.5\n\n  -1\n", exitOK,
+			"count   3\nsum     3\nmean    1\nmin     -1\nmax     2.5\nstddev  1.4719601443879744\n", ""},
+		{"args take precedence over stdin", []string{"stats", "3"}, "100\n", exitOK,
+			"count   1\nsum     3\nmean    3\nmin     3\nmax     3\nstddev  0\n", ""},
+		{"json", []string{"--json", "stats", "1", "3"}, "", exitOK,
+			`{"count":2,"sum":4,"mean":2,"min":1,"max":3,"stddev":1}` + "\n", ""},
+		{"empty stdin", []string{"stats"}, " \n", exitError, "", "stats: no numbers to summarize\n"},
+		{"invalid arg", []string{"stats", "1", "x"}, "", exitUsage, "", `invalid number "x"`},
+		{"non-finite arg", []string{"stats", "NaN"}, "", exitUsage, "", `invalid number "NaN"`},
+		{"overflow", []string{"stats", "1e308", "1e308"}, "", exitError, "", "stats: summary out of the range of float64\n"},
+		{"overflow json", []string{"--json", "stats", "1e308", "1e308"}, "", exitError, "", "stats: summary out of the range of float64\n"},
+		{"invalid stdin", []string{"stats"}, "1\n2 two\n", exitError, "", `stats: line 2: invalid number "two"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			code := newApp(strings.NewReader(tt.stdin), &out, &errOut).run(tt.args)
+			if code != tt.wantCode {
+				t.Fatalf("run(%q) = %d, want %d (stderr: %s)", tt.args, code, tt.wantCode, errOut.String())
+			}
+			if out.String() != tt.wantOut {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.wantOut)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErrOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+// maxSuggestDistance is the largest edit distance at which a mistyped
+// command name gets a suggestion.
+const maxSuggestDistance = 2
+
+// suggest returns the registered command closest to name, if one is within
+// maxSuggestDistance edits and fewer edits than name has characters, so
+// that a one- or two-letter typo is not "corrected" into an unrelated
+// word. Ties go to the command listed first.
+func suggest(name string) (string, bool) {
+	best, bestDist := "", maxSuggestDistance+1
+	for _, cmd := range commands() {
+		if d := levenshtein(name, cmd.name); d < bestDist {
+			best, bestDist = cmd.name, d
+		}
+	}
+	if best == "" || bestDist >= len([]rune(name)) {
+		return "", false
+	}
+	return best, true
+}
+
+// didYouMean returns a hint naming the command closest to name, starting
+// with "; ", or "" if there is none.
+func didYouMean(name string) string {
+	if s, ok := suggest(name); ok {
+		return "; did you mean " + s + "?"
+	}
+	return ""
+}
+
+// levenshtein returns the edit distance between a and b: the fewest
+// single-rune insertions, deletions and substitutions turning one into the
+// other.
+func levenshtein(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// prev[j] is the distance between the runes of s so far and t[:j].
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range s {
+		cur[0] = i + 1
+		for j := range t {
+			cost := 1
+			if s[i] == t[j] {
+				cost = 0
+			}
+			cur[j+1] = min(prev[j+1]+1, cur[j]+1, prev[j]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(t)]
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "greet", 5},
+		{"greet", "greet", 0},
+		{"gret", "greet", 1},
+		{"grete", "greet", 2},
+		{"kitten", "sitting", 3},
+		{"édit", "edit", 1},
+		{"docter", "doctor", 1},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := levenshtein(tt.b, tt.a); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name string
+		want string // "" for no suggestion
+	}{
+		{"gret", "greet"},
+		{"greeet", "greet"},
+		{"histroy", "history"},
+		{"docotr", "doctor"},
+		{"servve", "serve"},
+		{"versoin", "version"},
+		{"completoin", "completion"},
+		{"frobnicate", ""},
+		{"kubernetes", ""},
+		{"x", ""},
+		{"ab", ""},
+	}
+	for _, tt := range tests {
+		got, ok := suggest(tt.name)
+		if got != tt.want || ok != (tt.want != "") {
+			t.Errorf("suggest(%q) = %q, %v, want %q", tt.name, got, ok, tt.want)
+		}
+	}
+}
+
+func TestRunUnknownCommandSuggestion(t *testing.T) {
+	var out, errOut bytes.Buffer
+	if code := run([]string{"gret"}, &out, &errOut); code != exitUsage {
+		t.Errorf("run(gret) = %d, want %d", code, exitUsage)
+	}
+	if want := `unknown command "gret"; did you mean greet?`; !strings.Contains(errOut.String(), want) {
+		t.Errorf("stderr = %q, want it to contain %q", errOut.String(), want)
+	}
+
+	errOut.Reset()
+	run([]string{"frobnicate"}, &out, &errOut)
+	if strings.Contains(errOut.String(), "did you mean") {
+		t.Errorf("stderr = %q, want no suggestion for an unrelated word", errOut.String())
+	}
+}
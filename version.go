@@ -0,0 +1,85 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"runtime/debug"
+)
+
+// Build metadata injected with -ldflags, for example:
+//
+//	go build -ldflags "-X main.version=v1.2.0 -X main.commit=$(git rev-parse HEAD) -X main.date=$(date -u +%FT%TZ)"
+//
+// They are used when the binary carries no module or VCS build info.
+var version, commit, date string
+
+// readBuildInfo is replaced in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
+func versionCommand() command {
+	return command{
+		name:    "version",
+		summary: "Print the version, commit and build date",
+		setFlags: func(fs *flag.FlagSet) handler {
+			return func(a *app, args []string) (Output, error) {
+				return buildVersion(), nil
+			}
+		},
+	}
+}
+
+// versionInfo is the result of the version command.
+type versionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version,omitempty"`
+}
+
+// buildVersion collects version metadata from the embedded build info,
+// filling any field it lacks from the ldflag variables.
+func buildVersion() versionInfo {
+	var v versionInfo
+	if info, ok := readBuildInfo(); ok {
+		v.GoVersion = info.GoVersion
+		if info.Main.Version != "" && info.Main.Version != "(devel)" {
+			v.Version = info.Main.Version
+		}
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				v.Commit = s.Value
+			case "vcs.time":
+				v.Date = s.Value
+			case "vcs.modified":
+				v.Modified = s.Value == "true"
+			}
+		}
+	}
+	v.Version = firstNonEmpty(v.Version, version, "dev")
+	v.Commit = firstNonEmpty(v.Commit, commit, "unknown")
+	v.Date = firstNonEmpty(v.Date, date, "unknown")
+	return v
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (v versionInfo) Text(w io.Writer) error {
+	dirty := ""
+	if v.Modified {
+		dirty = " (modified)"
+	}
+	_, err := fmt.Fprintf(w, "%s %s\ncommit: %s%s\nbuilt:  %s\n", progName, v.Version, v.Commit, dirty, v.Date)
+	return err
+}
+
+func (v versionInfo) JSON(w io.Writer) error { return writeJSON(w, v) }
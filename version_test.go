@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"runtime/debug"
+	"testing"
+)
+
+// setBuildInfo replaces the build metadata sources for the duration of the test.
+func setBuildInfo(t *testing.T, info *debug.BuildInfo, ver, rev, built string) {
+	t.Helper()
+	origRead, origVersion, origCommit, origDate := readBuildInfo, version, commit, date
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, info != nil }
+	version, commit, date = ver, rev, built
+	t.Cleanup(func() {
+		readBuildInfo, version, commit, date = origRead, origVersion, origCommit, origDate
+	})
+}
+
+func TestBuildVersion(t *testing.T) {
+	vcsInfo := &debug.BuildInfo{
+		GoVersion: "go1.24.9",
+		Main:      debug.Module{Version: "v1.2.3"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "abc123"},
+			{Key: "vcs.time", Value: "2025-01-02T03:04:05Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	develInfo := &debug.BuildInfo{GoVersion: "go1.24.9", Main: debug.Module{Version: "(devel)"}}
+
+	tests := []struct {
+		name                        string
+		info                        *debug.BuildInfo
+		ldVersion, ldCommit, ldDate string
+		expected                    versionInfo
+	}{
+		{
+			"build info wins", vcsInfo, "v0.0.1", "ffff", "yesterday",
+			versionInfo{Version: "v1.2.3", Commit: "abc123", Date: "2025-01-02T03:04:05Z", Modified: true, GoVersion: "go1.24.9"},
+		},
+		{
+			"devel build falls back to ldflags", develInfo, "v0.0.1", "ffff", "yesterday",
+			versionInfo{Version: "v0.0.1", Commit: "ffff", Date: "yesterday", GoVersion: "go1.24.9"},
+		},
+		{
+			"no build info uses ldflags", nil, "v0.0.1", "ffff", "yesterday",
+			versionInfo{Version: "v0.0.1", Commit: "ffff", Date: "yesterday"},
+		},
+		{
+			"nothing available", nil, "", "", "",
+			versionInfo{Version: "dev", Commit: "unknown", Date: "unknown"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setBuildInfo(t, tt.info, tt.ldVersion, tt.ldCommit, tt.ldDate)
+			if got := buildVersion(); got != tt.expected {
+				t.Errorf("buildVersion() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestVersionCommand(t *testing.T) {
+	setBuildInfo(t, nil, "v0.9.0", "deadbeef", "2025-06-01")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"text", []string{"version"}, progName + " v0.9.0\ncommit: deadbeef\nbuilt:  2025-06-01\n"},
+		{"json", []string{"--json", "version"}, `{"version":"v0.9.0","commit":"deadbeef","date":"2025-06-01"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			if code := run(tt.args, &out, &errOut); code != exitOK {
+				t.Fatalf("run(%q) = %d, want %d", tt.args, code, exitOK)
+			}
+			if out.String() != tt.want {
+				t.Errorf("stdout = %q, want %q", out.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+//go:build js && wasm
+
+// Command wasm exposes the example functions to JavaScript. Built with
+// GOOS=js GOARCH=wasm (see make wasm), it registers these globals:
+//
+//	greet(name)  // "Hello, <name>!"; an empty name greets the World
+//	add(a, b)    // a + b for safe integers
+//
+// Bad arguments make the functions return a JavaScript Error object
+// rather than throwing.
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/ericbfriday/claude-go-containers/examples"
+)
+
+func main() {
+	js.Global().Set("greet", export(greet))
+	js.Global().Set("add", export(add))
+	// The functions stay callable only while the program runs.
+	select {}
+}
+
+// greet greets its single string argument in English, so the result does
+// not depend on the visitor's clock.
+func greet(args []js.Value) (any, error) {
+	if err := wantArgs(args, 1); err != nil {
+		return nil, err
+	}
+	name, err := stringArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	return examples.GreetLocalized(name, "en"), nil
+}
+
+// add adds its two integer arguments, rejecting sums JavaScript cannot
+// represent exactly.
+func add(args []js.Value) (any, error) {
+	if err := wantArgs(args, 2); err != nil {
+		return nil, err
+	}
+	a, err := intArg(args, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, err := intArg(args, 1)
+	if err != nil {
+		return nil, err
+	}
+	sum, err := examples.AddChecked(a, b)
+	if err != nil {
+		return nil, err
+	}
+	if sum > maxSafeInteger || sum < -maxSafeInteger {
+		return nil, errUnsafeResult
+	}
+	return sum, nil
+}
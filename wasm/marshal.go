@@ -0,0 +1,61 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"syscall/js"
+)
+
+// maxSafeInteger is JavaScript's Number.MAX_SAFE_INTEGER, the largest
+// integer a number holds exactly.
+const maxSafeInteger = 1<<53 - 1
+
+var errUnsafeResult = errors.New("result is outside the safe integer range")
+
+// export wraps fn as a JavaScript function. An error from fn is returned
+// to JavaScript as an Error object.
+func export(fn func(args []js.Value) (any, error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		v, err := fn(args)
+		if err != nil {
+			return jsError(err)
+		}
+		return v
+	})
+}
+
+// jsError converts err to a JavaScript Error with the same message.
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
+
+// wantArgs checks that exactly n arguments were passed.
+func wantArgs(args []js.Value, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("want %d arguments, got %d", n, len(args))
+	}
+	return nil
+}
+
+// stringArg returns args[i] if it is a string.
+func stringArg(args []js.Value, i int) (string, error) {
+	if t := args[i].Type(); t != js.TypeString {
+		return "", fmt.Errorf("argument %d: want string, got %s", i+1, t)
+	}
+	return args[i].String(), nil
+}
+
+// intArg returns args[i] if it is a number holding a safe integer.
+func intArg(args []js.Value, i int) (int, error) {
+	if t := args[i].Type(); t != js.TypeNumber {
+		return 0, fmt.Errorf("argument %d: want number, got %s", i+1, t)
+	}
+	f := args[i].Float()
+	if f != math.Trunc(f) || math.Abs(f) > maxSafeInteger {
+		return 0, fmt.Errorf("argument %d: %v is not a safe integer", i+1, f)
+	}
+	return int(f), nil
+}
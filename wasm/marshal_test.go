@@ -0,0 +1,130 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"math"
+	"strings"
+	"syscall/js"
+	"testing"
+)
+
+func TestStringArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     any
+		want    string
+		wantErr string
+	}{
+		{"string", "Alice", "Alice", ""},
+		{"empty string", "", "", ""},
+		{"number", 3, "", "want string, got number"},
+		{"null", nil, "", "want string, got null"},
+		{"undefined", js.Undefined(), "", "want string, got undefined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := stringArg([]js.Value{js.ValueOf(tt.arg)}, 0)
+			checkArg(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestIntArg(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     any
+		want    int
+		wantErr string
+	}{
+		{"integer", 3, 3, ""},
+		{"negative", -7, -7, ""},
+		{"integral float", 2.0, 2, ""},
+		{"largest safe", maxSafeInteger, maxSafeInteger, ""},
+		{"fraction", 2.5, 0, "not a safe integer"},
+		{"unsafe", float64(1 << 60), 0, "not a safe integer"},
+		{"NaN", math.NaN(), 0, "not a safe integer"},
+		{"string", "3", 0, "want number, got string"},
+		{"boolean", true, 0, "want number, got boolean"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := intArg([]js.Value{js.ValueOf(tt.arg)}, 0)
+			checkArg(t, got, err, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func checkArg[T comparable](t *testing.T, got T, err error, want T, wantErr string) {
+	t.Helper()
+	if wantErr != "" {
+		if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Fatalf("error = %v, want it to contain %q", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestExportedFunctions(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func([]js.Value) (any, error)
+		args    []any
+		want    any
+		wantErr string
+	}{
+		{"greet", greet, []any{"Alice"}, "Hello, Alice!", ""},
+		{"greet empty", greet, []any{""}, "Hello, World!", ""},
+		{"greet number", greet, []any{1}, nil, "want string"},
+		{"greet no args", greet, nil, nil, "want 1 arguments, got 0"},
+		{"add", add, []any{2, 3}, 5, ""},
+		{"add string", add, []any{2, "3"}, nil, "argument 2: want number"},
+		{"add one arg", add, []any{2}, nil, "want 2 arguments, got 1"},
+		{"add unsafe sum", add, []any{maxSafeInteger, 1}, nil, "safe integer range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := make([]js.Value, len(tt.args))
+			for i, a := range tt.args {
+				args[i] = js.ValueOf(a)
+			}
+			got, err := tt.fn(args)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportReturnsErrorObject(t *testing.T) {
+	fn := export(add)
+	defer fn.Release()
+	got := fn.Invoke("two", 3)
+	if !got.InstanceOf(js.Global().Get("Error")) {
+		t.Fatalf("add(\"two\", 3) = %v, want an Error", got)
+	}
+	if msg := got.Get("message").String(); !strings.Contains(msg, "want number, got string") {
+		t.Errorf("Error message = %q", msg)
+	}
+	if got := fn.Invoke(2, 3); got.Int() != 5 {
+		t.Errorf("add(2, 3) = %v, want 5", got)
+	}
+}
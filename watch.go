@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ericbfriday/claude-go-containers/watch"
+)
+
+func watchCommand() command {
+	return command{
+		name:    "watch",
+		args:    "[--dir DIR] [--debounce D] -- <command> [args]",
+		summary: "Re-run a command whenever files under a directory change",
+		setFlags: func(fs *flag.FlagSet) handler {
+			dir := fs.String("dir", ".", "directory tree to watch")
+			debounce := fs.Duration("debounce", watch.DefaultDebounce, "wait this long after the last change before re-running")
+			return func(a *app, args []string) (Output, error) {
+				if len(args) == 0 {
+					return nil, usagef("a command to run is required")
+				}
+				cmd, ok := lookup(args[0])
+				if !ok {
+					return nil, usagef("unknown command %q%s", args[0], didYouMean(args[0]))
+				}
+				switch cmd.name {
+				case "watch", "serve", "repl":
+					return nil, usagef("cannot watch %s", cmd.name)
+				}
+				if *debounce <= 0 {
+					return nil, usagef("--debounce must be positive")
+				}
+				ctx, stop := interruptContext()
+				defer stop()
+				return nil, a.watch(ctx, *dir, *debounce, cmd, args[1:])
+			}
+		},
+	}
+}
+
+// watch runs cmd with args, then again each time files under dir change,
+// until ctx is done. A failing run is reported like any other command
+// failure and does not stop the watcher. Runs are separated by a line on
+// errOut so stdout holds only command output.
+func (a *app) watch(ctx context.Context, dir string, debounce time.Duration, cmd command, args []string) error {
+	w, err := watch.New(dir, a.logger)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	line := strings.Join(append([]string{cmd.name}, args...), " ")
+	rerun := func() {
+		if code := a.dispatch(cmd, args); code != exitOK {
+			a.logger.Info("watched command failed", "command", line, "exit", code)
+		}
+	}
+	rerun()
+	return w.Run(ctx, debounce, func() {
+		fmt.Fprintf(a.errOut, "--- %s: files changed, re-running %s ---\n", time.Now().Format(time.TimeOnly), line)
+		rerun()
+	})
+}
@@ -0,0 +1,129 @@
+// Package watch reports changes to the files under a directory tree,
+// coalescing bursts of events, such as an editor saving several files, into
+// a single notification.
+package watch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long a tree must be quiet after a change before
+// Run reports it.
+const DefaultDebounce = 200 * time.Millisecond
+
+// Watcher watches every directory under a root. Watching directories rather
+// than files means a file that is deleted and recreated, as many editors do
+// on save, is still seen. Files and directories whose names start with a
+// dot, such as .git or an editor's swap files, are ignored.
+type Watcher struct {
+	fsw    *fsnotify.Watcher
+	logger *slog.Logger
+}
+
+// New starts watching the tree rooted at dir. Changes made after New
+// returns are reported by Run. logger may be nil.
+func New(dir string, logger *slog.Logger) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	w := &Watcher{fsw: fsw, logger: logger}
+	if err := w.addTree(dir); err != nil {
+		fsw.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Close stops watching and releases the watcher's resources.
+func (w *Watcher) Close() error {
+	return w.fsw.Close()
+}
+
+// Run calls onChange each time files change, once the tree has been quiet
+// for debounce, until ctx is done. onChange runs on Run's goroutine; changes
+// made while it runs are reported after it returns. Run returns nil when ctx
+// is done and an error if the watcher fails.
+func (w *Watcher) Run(ctx context.Context, debounce time.Duration, onChange func()) error {
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return nil
+			}
+			if !w.handle(ev) {
+				continue
+			}
+			// Each event pushes the deadline back, so a burst of events
+			// is reported once.
+			timer.Reset(debounce)
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
+
+// handle records ev, watching any directory it creates, and reports
+// whether it counts as a change.
+func (w *Watcher) handle(ev fsnotify.Event) bool {
+	// Metadata changes alone, such as a backup tool touching
+	// permissions, do not change what a command would see.
+	if ev.Op == fsnotify.Chmod || hidden(ev.Name) {
+		return false
+	}
+	w.logger.Debug("file changed", "path", ev.Name, "op", ev.Op.String())
+	if ev.Has(fsnotify.Create) {
+		// A new directory may already hold files by the time it is
+		// watched, so its whole tree is added.
+		if err := w.addTree(ev.Name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			w.logger.Warn("cannot watch new directory", "path", ev.Name, "err", err)
+		}
+	}
+	return true
+}
+
+// addTree watches root and every directory beneath it. A root that is not
+// a directory is ignored.
+func (w *Watcher) addTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			// Entries may vanish between the event and the walk.
+			if path != root && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != root && hidden(path) {
+			return filepath.SkipDir
+		}
+		return w.fsw.Add(path)
+	})
+}
+
+func hidden(path string) bool {
+	return strings.HasPrefix(filepath.Base(path), ".")
+}
@@ -0,0 +1,139 @@
+package watch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testDebounce = 50 * time.Millisecond
+
+// start watches dir and returns a channel receiving a value per reported
+// change.
+func start(t *testing.T, dir string) <-chan struct{} {
+	t.Helper()
+	w, err := New(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	changes := make(chan struct{}, 16)
+	done := make(chan error, 1)
+	go func() { done <- w.Run(ctx, testDebounce, func() { changes <- struct{}{} }) }()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+		w.Close()
+	})
+	return changes
+}
+
+// changesWithin counts the changes reported in d.
+func changesWithin(changes <-chan struct{}, d time.Duration) int {
+	n := 0
+	timeout := time.After(d)
+	for {
+		select {
+		case <-changes:
+			n++
+		case <-timeout:
+			return n
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(t *testing.T, dir string)
+		change func(t *testing.T, dir string)
+		want   int
+	}{
+		{
+			name: "burst of writes is coalesced",
+			change: func(t *testing.T, dir string) {
+				for _, name := range []string{"a.go", "b.go", "a.go"} {
+					writeFile(t, filepath.Join(dir, name), name)
+				}
+			},
+			want: 1,
+		},
+		{
+			name:  "delete and recreate",
+			setup: func(t *testing.T, dir string) { writeFile(t, filepath.Join(dir, "main.go"), "v1") },
+			change: func(t *testing.T, dir string) {
+				path := filepath.Join(dir, "main.go")
+				if err := os.Remove(path); err != nil {
+					t.Fatal(err)
+				}
+				writeFile(t, path, "v2")
+			},
+			want: 1,
+		},
+		{
+			name:  "existing subdirectory",
+			setup: func(t *testing.T, dir string) { os.MkdirAll(filepath.Join(dir, "pkg", "sub"), 0o755) },
+			change: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, "pkg", "sub", "x.go"), "x")
+			},
+			want: 1,
+		},
+		{
+			name:  "hidden directories are ignored",
+			setup: func(t *testing.T, dir string) { os.Mkdir(filepath.Join(dir, ".git"), 0o755) },
+			change: func(t *testing.T, dir string) {
+				writeFile(t, filepath.Join(dir, ".git", "index"), "x")
+				writeFile(t, filepath.Join(dir, ".main.go.swp"), "x")
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.setup != nil {
+				tt.setup(t, dir)
+			}
+			changes := start(t, dir)
+			tt.change(t, dir)
+			if got := changesWithin(changes, 5*testDebounce); got != tt.want {
+				t.Errorf("got %d changes, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunWatchesNewDirectories(t *testing.T) {
+	dir := t.TempDir()
+	changes := start(t, dir)
+
+	sub := filepath.Join(dir, "new")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if got := changesWithin(changes, 5*testDebounce); got != 1 {
+		t.Fatalf("got %d changes after mkdir, want 1", got)
+	}
+	writeFile(t, filepath.Join(sub, "x.go"), "x")
+	if got := changesWithin(changes, 5*testDebounce); got != 1 {
+		t.Errorf("got %d changes for a file in the new directory, want 1", got)
+	}
+}
+
+func TestNewMissingDirectory(t *testing.T) {
+	if _, err := New(filepath.Join(t.TempDir(), "missing"), nil); err == nil {
+		t.Error("New() error = nil for a missing directory")
+	}
+}
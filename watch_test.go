@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lockedBuffer is a strings.Builder that is safe to read while a command
+// running on another goroutine writes to it.
+type lockedBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+// waitFor polls until s appears n times in b, failing the test after five
+// seconds.
+func waitFor(t *testing.T, b *lockedBuffer, s string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for strings.Count(b.String(), s) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("waiting for %d × %q, got:\n%s", n, s, b.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		stdout bool   // whether want is written to stdout rather than stderr
+		want   string // written once per run
+	}{
+		{"reruns on change", []string{"add", "2", "3"}, true, "5\n"},
+		{"failing command keeps watching", []string{"add", "x"}, false, `add: invalid integer "x"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			var out, errOut lockedBuffer
+			a := newApp(strings.NewReader(""), &out, &errOut)
+			cmd, _ := lookup(tt.args[0])
+			stream := &errOut
+			if tt.stdout {
+				stream = &out
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			done := make(chan error, 1)
+			go func() { done <- a.watch(ctx, dir, 20*time.Millisecond, cmd, tt.args[1:]) }()
+			waitFor(t, stream, tt.want, 1)
+
+			if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			waitFor(t, stream, tt.want, 2)
+			waitFor(t, &errOut, "files changed, re-running "+strings.Join(tt.args, " ")+" ---\n", 1)
+
+			cancel()
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Errorf("watch() error = %v, want nil after cancellation", err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("watch did not return after cancellation")
+			}
+		})
+	}
+}
+
+func TestWatchCommandErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no command", []string{"watch"}, "a command to run is required"},
+		{"unknown command", []string{"watch", "--", "nope"}, `unknown command "nope"`},
+		{"nested watch", []string{"watch", "--", "watch", "add"}, "cannot watch watch"},
+		{"bad debounce", []string{"watch", "--debounce", "0s", "--", "add"}, "--debounce must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut strings.Builder
+			if code := run(tt.args, &out, &errOut); code != exitUsage {
+				t.Errorf("run(%v) = %d, want %d", tt.args, code, exitUsage)
+			}
+			if !strings.Contains(errOut.String(), tt.wantErr) {
+				t.Errorf("stderr = %q, want it to contain %q", errOut.String(), tt.wantErr)
+			}
+		})
+	}
+}